@@ -0,0 +1,40 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// CaptureClose closes c and joins its error into errp, it is designed for defer:
+//
+//	defer errors.CaptureClose(&err, f, "unable to close file: ", name)
+//
+// Closing an already closed file is not reported.
+func CaptureClose(errp *error, c io.Closer, a ...any) {
+	err := c.Close()
+	if Is(err, fs.ErrClosed) {
+		return
+	}
+	capture(errp, err, a)
+}
+
+// Capture calls fn and joins its error into errp, it is designed for defer:
+//
+//	defer errors.Capture(&err, w.Flush, "unable to flush")
+func Capture(errp *error, fn func() error, a ...any) {
+	capture(errp, fn(), a)
+}
+
+func capture(errp *error, err error, a []any) {
+	if err == nil {
+		return
+	}
+
+	err = WithStack(err, 5, fmt.Sprint(a...))
+	if *errp == nil {
+		*errp = err
+		return
+	}
+	*errp = Join(*errp, err)
+}
@@ -0,0 +1,74 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failCloser struct {
+	err error
+}
+
+func (c failCloser) Close() error {
+	return c.err
+}
+
+func closeWith(c io.Closer, result error) (err error) {
+	defer errors.CaptureClose(&err, c, "unable to close")
+	return result
+}
+
+func TestCaptureClose(t *testing.T) {
+	assert.NoError(t, closeWith(failCloser{}, nil))
+	assert.Equal(t, io.EOF, closeWith(failCloser{}, io.EOF))
+
+	err := closeWith(failCloser{os.ErrInvalid}, nil)
+	assert.Equal(t, "unable to close", err.Error())
+	assert.True(t, errors.Is(err, os.ErrInvalid))
+	assert.Contains(t, fmt.Sprintf("%+v", err), "errors_test.closeWith")
+
+	err = closeWith(failCloser{os.ErrInvalid}, io.EOF)
+	assert.Equal(t, 2, errors.Count(err))
+	assert.True(t, errors.Is(err, io.EOF))
+	assert.True(t, errors.Is(err, os.ErrInvalid))
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "closed"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.NoError(t, closeWith(f, nil))
+}
+
+func TestCaptureCloseSharedJoin(t *testing.T) {
+	base := errors.Join(errors.Error("a"), errors.Error("b"))
+	c1, c2 := errors.Error("c1"), errors.Error("c2")
+
+	err1 := closeWith(failCloser{c1}, base)
+	err2 := closeWith(failCloser{c2}, base)
+	assert.Equal(t, 2, errors.Count(base))
+	assert.Equal(t, "a\nb", base.Error())
+	assert.Equal(t, 3, errors.Count(err1))
+	assert.Equal(t, 3, errors.Count(err2))
+	assert.True(t, errors.Is(err1, c1))
+	assert.False(t, errors.Is(err1, c2))
+	assert.True(t, errors.Is(err2, c2))
+	assert.False(t, errors.Is(err2, c1))
+}
+
+func TestCapture(t *testing.T) {
+	run := func(fn func() error) (err error) {
+		defer errors.Capture(&err, fn)
+		return nil
+	}
+
+	assert.NoError(t, run(func() error { return nil }))
+	err := run(func() error { return io.ErrShortWrite })
+	assert.True(t, errors.Is(err, io.ErrShortWrite))
+	assert.Equal(t, io.ErrShortWrite.Error(), err.Error())
+}
@@ -0,0 +1,190 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultVerboseEnv = "GOBASE_VERBOSE"
+
+	defaultExitCode  = 1
+	maxSummaryErrors = 10
+)
+
+// ExitCoder can be implemented by an error to choose the exit code of a CLI tool.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+type exitCodeEntry struct {
+	target error
+	code   int
+}
+
+var (
+	exitCodeMu      sync.RWMutex
+	exitCodeEntries []exitCodeEntry
+)
+
+// RegisterExitCode maps the errors matched by Is(err, target) to code,
+// the first registered target wins. An error kind can be used as target.
+func RegisterExitCode(target error, code int) {
+	exitCodeMu.Lock()
+	defer exitCodeMu.Unlock()
+	exitCodeEntries = append(exitCodeEntries, exitCodeEntry{target: target, code: code})
+}
+
+// ExitCode chooses the exit code of err, 0 for nil.
+// An ExitCoder in the chain is preferred, then the registered targets, otherwise 1.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var coder ExitCoder
+	if As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	exitCodeMu.RLock()
+	defer exitCodeMu.RUnlock()
+	for _, entry := range exitCodeEntries {
+		if Is(err, entry.target) {
+			return entry.code
+		}
+	}
+
+	return defaultExitCode
+}
+
+// UserMessage renders err concisely for end users:
+// the messages of the chain are joined by ": " and joined errors are summarized.
+func UserMessage(err error) string {
+	return userMessage(err, func(e *stackErr) string {
+		return e.msg
+	}, func(n int) string {
+		return fmt.Sprintf("%d errors occurred:", n)
+	})
+}
+
+func userMessage(err error, msgOf func(e *stackErr) string, summaryOf func(n int) string) string {
+	if err == nil {
+		return ""
+	}
+
+	parts := make([]string, 0, 2)
+	appendPart := func(part string) {
+		if len(parts) == 0 || parts[len(parts)-1] != part {
+			parts = append(parts, part)
+		}
+	}
+
+loop:
+	for err != nil {
+		switch e := err.(type) {
+		case *multiErr:
+			appendPart(summarizeMultiErr(e, msgOf, summaryOf))
+			break loop
+		case *stackErr:
+			appendPart(msgOf(e))
+			err = e.cause
+		default:
+			appendPart(err.Error())
+			break loop
+		}
+	}
+
+	return strings.Join(parts, ": ")
+}
+
+func summarizeMultiErr(e *multiErr, msgOf func(e *stackErr) string, summaryOf func(n int) string) string {
+	leaves := Leaves(e)
+	if len(leaves) == 1 {
+		return userMessage(leaves[0], msgOf, summaryOf)
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(summaryOf(len(leaves)))
+	for i, leaf := range leaves {
+		if i >= maxSummaryErrors {
+			fmt.Fprintf(sb, "\n\t* and %d more", len(leaves)-i)
+			break
+		}
+		fmt.Fprintf(sb, "\n\t* %s", userMessage(leaf, msgOf, summaryOf))
+	}
+	return sb.String()
+}
+
+// Reporter prints the error of a CLI tool and exits with the code chosen by ExitCode.
+type Reporter struct {
+	Out  io.Writer
+	Exit func(code int)
+	// Name is printed before the message, "error" is used if empty.
+	Name string
+	// Verbose prints the details of the error by GetErrorDetails.
+	Verbose bool
+	// VerboseEnv enables Verbose if the environment variable is set to true.
+	VerboseEnv string
+}
+
+func NewReporter() *Reporter {
+	r := &Reporter{}
+	r.Out = os.Stderr
+	r.Exit = os.Exit
+	r.VerboseEnv = DefaultVerboseEnv
+	return r
+}
+
+func (r *Reporter) isVerbose() bool {
+	if r.Verbose {
+		return true
+	}
+	if r.VerboseEnv == "" {
+		return false
+	}
+	v, err := strconv.ParseBool(os.Getenv(r.VerboseEnv))
+	return err == nil && v
+}
+
+// Report prints err and returns its exit code, nothing is printed for nil.
+func (r *Reporter) Report(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	name := r.Name
+	if name == "" {
+		name = "error"
+	}
+
+	fmt.Fprintf(r.Out, "%s: %s\n", name, UserMessage(err))
+	if r.isVerbose() {
+		details := strings.TrimPrefix(GetErrorDetails(err), "\n")
+		io.WriteString(r.Out, details)
+		if !strings.HasSuffix(details, "\n") {
+			io.WriteString(r.Out, "\n")
+		}
+	}
+
+	return ExitCode(err)
+}
+
+// ExitOnError reports err and exits, it does nothing for nil.
+func (r *Reporter) ExitOnError(err error) {
+	if err == nil {
+		return
+	}
+	r.Exit(r.Report(err))
+}
+
+var defaultReporter = NewReporter()
+
+// Exit reports err to stderr and exits the process with its exit code, 0 for nil.
+func Exit(err error) {
+	os.Exit(defaultReporter.Report(err))
+}
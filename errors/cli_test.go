@@ -0,0 +1,86 @@
+package errors_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+func (e usageError) ExitCode() int {
+	return 2
+}
+
+func TestExitCode(t *testing.T) {
+	errors.RegisterExitCode(errors.KindNotFound, 3)
+	errors.RegisterExitCode(os.ErrPermission, 4)
+
+	assert.Equal(t, 0, errors.ExitCode(nil))
+	assert.Equal(t, 1, errors.ExitCode(io.EOF))
+	assert.Equal(t, 2, errors.ExitCode(errors.ErrorAt(usageError("bad flag"))))
+	assert.Equal(t, 3, errors.ExitCode(errors.ErrorAt(errors.WithKind(io.EOF, errors.KindNotFound))))
+	assert.Equal(t, 4, errors.ExitCode(errors.Join(io.EOF, fmt.Errorf("open: %w", os.ErrPermission))))
+}
+
+func TestUserMessage(t *testing.T) {
+	assert.Equal(t, "", errors.UserMessage(nil))
+	assert.Equal(t, "EOF", errors.UserMessage(io.EOF))
+
+	err := errors.ErrorAt(io.EOF, "read config")
+	err = errors.ErrorAt(err)
+	err = errors.ErrorAt(err, "start app")
+	assert.Equal(t, "start app: read config: EOF", errors.UserMessage(err))
+
+	err = errors.ErrorAt(errors.Join(io.EOF, errors.Join(os.ErrClosed, errors.Error("job failed"))), "batch")
+	assert.Equal(t, "batch: 3 errors occurred:\n\t* EOF\n\t* file already closed\n\t* job failed", errors.UserMessage(err))
+
+	assert.Equal(t, "EOF", errors.UserMessage(errors.Join(io.EOF)))
+
+	errs := make([]error, 0, 12)
+	for i := 0; i < 12; i++ {
+		errs = append(errs, errors.Errorf("err%d", i))
+	}
+	assert.Contains(t, errors.UserMessage(errors.Join(errs...)), "12 errors occurred:\n\t* err0\n")
+	assert.Contains(t, errors.UserMessage(errors.Join(errs...)), "\n\t* err9\n\t* and 2 more")
+}
+
+func TestReporter(t *testing.T) {
+	out := &bytes.Buffer{}
+	code := -1
+	r := errors.NewReporter()
+	r.Out = out
+	r.Exit = func(c int) { code = c }
+	r.Name = "mytool"
+	r.VerboseEnv = "GOBASE_TEST_VERBOSE"
+
+	r.ExitOnError(nil)
+	assert.Equal(t, -1, code)
+	assert.Empty(t, out.String())
+
+	err := errors.ErrorAt(usageError("unknown flag -x"), "parse args")
+	r.ExitOnError(err)
+	assert.Equal(t, 2, code)
+	assert.Equal(t, "mytool: parse args: unknown flag -x\n", out.String())
+
+	out.Reset()
+	t.Setenv("GOBASE_TEST_VERBOSE", "true")
+	assert.Equal(t, 2, r.Report(err))
+	assert.Contains(t, out.String(), "mytool: parse args: unknown flag -x\n* unknown flag -x\n* parse args\n\t* ")
+
+	out.Reset()
+	t.Setenv("GOBASE_TEST_VERBOSE", "0")
+	r.Verbose = true
+	r.Name = ""
+	r.Report(io.EOF)
+	assert.Equal(t, "error: EOF\nEOF\n", out.String())
+}
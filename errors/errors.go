@@ -3,8 +3,6 @@ package errors
 import (
 	"fmt"
 	"io"
-	"path"
-	"runtime"
 )
 
 const (
@@ -12,9 +10,14 @@ const (
 )
 
 type stackErr struct {
-	msg   string
-	cause error
-	stack stack
+	msg    string
+	cause  error
+	stack  *stack
+	fields []Field
+	kind   Kind
+
+	panicValue any
+	template   *msgTemplate
 }
 
 func Error(a ...any) error {
@@ -43,8 +46,9 @@ func WithStack(err error, callDepth int, msg string) error {
 		}
 	}
 	werr.msg = msg
-	werr.stack = getStack(callDepth)
+	werr.stack = getStack(callDepth, err)
 	werr.cause = err
+	runHooks(werr)
 	return werr
 }
 
@@ -75,23 +79,29 @@ func GetErrorDetails(err error) string {
 	return fmt.Sprintf("\n%+v", err)
 }
 
-type stack []runtime.Frame
-
-func getStack(skip int) stack {
-	callers := make([]uintptr, 64)
-	n := runtime.Callers(skip, callers)
+// attach applies fn to a copy of err if err is created by this package,
+// otherwise err is wrapped with the stack of the caller's caller.
+func attach(err error, fn func(e *stackErr)) error {
+	if err == nil {
+		return nil
+	}
 
-	s := make([]runtime.Frame, 0, 5)
-	frames := runtime.CallersFrames(callers[:n])
-	for {
-		frame, more := frames.Next()
-		s = append(s, frame)
-		if !more {
-			break
-		}
+	e, ok := err.(*stackErr)
+	if !ok {
+		e = WithStack(err, 5, "").(*stackErr)
+	} else {
+		e = e.clone()
 	}
 
-	return s
+	fn(e)
+	return e
+}
+
+func (e *stackErr) clone() *stackErr {
+	newe := &stackErr{}
+	*newe = *e
+	newe.fields = append([]Field(nil), e.fields...)
+	return newe
 }
 
 func (e *stackErr) Error() string {
@@ -102,31 +112,15 @@ func (e *stackErr) Unwrap() error {
 	return e.cause
 }
 
+func (e *stackErr) Is(target error) bool {
+	k, ok := target.(Kind)
+	return ok && k != KindUnknown && e.kind == k
+}
+
 func (e *stackErr) Format(state fmt.State, verb rune) {
 	switch verb {
 	case 'v':
-		if e.cause != nil {
-			if cause, ok := e.cause.(fmt.Formatter); ok {
-				cause.Format(state, verb)
-			} else {
-				fmt.Fprintf(state, "* %v\n", e.cause)
-			}
-		}
-
-		fmt.Fprintf(state, "* %s\n", e.msg)
-		if state.Flag('+') {
-			frameCount, showFrame := state.Width()
-			if !showFrame {
-				frameCount = 1
-			}
-			frames := e.stack
-			if len(frames) > frameCount {
-				frames = e.stack[:frameCount]
-			}
-			for _, frame := range frames {
-				fmt.Fprintf(state, "\t* %s:%d %s\n", frame.File, frame.Line, path.Base(frame.Function))
-			}
-		}
+		e.printDetail(state, newPrinter(state, verb))
 	case 's':
 		io.WriteString(state, e.msg)
 	case 'q':
@@ -6,6 +6,7 @@ import (
 	"path"
 	"runtime"
 	"strings"
+	"testing"
 
 	"github.com/jopbrown/gobase/errors"
 )
@@ -22,7 +23,7 @@ func ExampleError() {
 
 	// Output:
 	// * the error:unable to got resource
-	// 	* github.com/jopbrown/gobase/errors/errors_test.go:20 errors_test.ExampleError
+	// 	* github.com/jopbrown/gobase/errors/errors_test.go:21 errors_test.ExampleError
 }
 
 func ExampleErrorOmit() {
@@ -31,7 +32,7 @@ func ExampleErrorOmit() {
 
 	// Output:
 	// * something is wrong
-	// 	* github.com/jopbrown/gobase/errors/errors_test.go:29 errors_test.ExampleErrorOmit
+	// 	* github.com/jopbrown/gobase/errors/errors_test.go:30 errors_test.ExampleErrorOmit
 }
 
 func ExampleErrorf() {
@@ -40,7 +41,7 @@ func ExampleErrorf() {
 
 	// Output:
 	// * the error:unable to got resource
-	// 	* github.com/jopbrown/gobase/errors/errors_test.go:38 errors_test.ExampleErrorf
+	// 	* github.com/jopbrown/gobase/errors/errors_test.go:39 errors_test.ExampleErrorf
 }
 
 func ExampleErrorAt() {
@@ -50,7 +51,7 @@ func ExampleErrorAt() {
 	// Output:
 	// * EOF
 	// * the error:unable to got resource
-	// 	* github.com/jopbrown/gobase/errors/errors_test.go:47 errors_test.ExampleErrorAt
+	// 	* github.com/jopbrown/gobase/errors/errors_test.go:48 errors_test.ExampleErrorAt
 }
 
 func ExampleErrorAt2() {
@@ -63,13 +64,13 @@ func ExampleErrorAt2() {
 	// Output:
 	// * EOF
 	// * 1st layer error
-	// 	* github.com/jopbrown/gobase/errors/errors_test.go:57 errors_test.ExampleErrorAt2
-	// * 2nd layer error
 	// 	* github.com/jopbrown/gobase/errors/errors_test.go:58 errors_test.ExampleErrorAt2
-	// * 3rd layer error
+	// * 2nd layer error
 	// 	* github.com/jopbrown/gobase/errors/errors_test.go:59 errors_test.ExampleErrorAt2
-	// * 4th layer error
+	// * 3rd layer error
 	// 	* github.com/jopbrown/gobase/errors/errors_test.go:60 errors_test.ExampleErrorAt2
+	// * 4th layer error
+	// 	* github.com/jopbrown/gobase/errors/errors_test.go:61 errors_test.ExampleErrorAt2
 }
 
 func ExampleErrorAtOmit() {
@@ -82,13 +83,13 @@ func ExampleErrorAtOmit() {
 	// Output:
 	// * EOF
 	// * something is wrong
-	// 	* github.com/jopbrown/gobase/errors/errors_test.go:76 errors_test.ExampleErrorAtOmit
-	// * something is wrong
 	// 	* github.com/jopbrown/gobase/errors/errors_test.go:77 errors_test.ExampleErrorAtOmit
 	// * something is wrong
 	// 	* github.com/jopbrown/gobase/errors/errors_test.go:78 errors_test.ExampleErrorAtOmit
 	// * something is wrong
 	// 	* github.com/jopbrown/gobase/errors/errors_test.go:79 errors_test.ExampleErrorAtOmit
+	// * something is wrong
+	// 	* github.com/jopbrown/gobase/errors/errors_test.go:80 errors_test.ExampleErrorAtOmit
 }
 
 func ExampleErrorAtf() {
@@ -101,11 +102,68 @@ func ExampleErrorAtf() {
 	// Output:
 	// * EOF
 	// * 1 err
-	// 	* github.com/jopbrown/gobase/errors/errors_test.go:97 errors_test.ExampleErrorAtf
+	// 	* github.com/jopbrown/gobase/errors/errors_test.go:98 errors_test.ExampleErrorAtf
 	// * 2 err
-	// 	* github.com/jopbrown/gobase/errors/errors_test.go:97 errors_test.ExampleErrorAtf
+	// 	* github.com/jopbrown/gobase/errors/errors_test.go:98 errors_test.ExampleErrorAtf
 	// * 3 err
-	// 	* github.com/jopbrown/gobase/errors/errors_test.go:97 errors_test.ExampleErrorAtf
+	// 	* github.com/jopbrown/gobase/errors/errors_test.go:98 errors_test.ExampleErrorAtf
 	// * 4 err
-	// 	* github.com/jopbrown/gobase/errors/errors_test.go:97 errors_test.ExampleErrorAtf
+	// 	* github.com/jopbrown/gobase/errors/errors_test.go:98 errors_test.ExampleErrorAtf
+}
+
+func wrapDeep(depth int) error {
+	if depth == 0 {
+		return errors.Error("deep error")
+	}
+	return errors.ErrorAt(wrapDeep(depth-1), "layer")
+}
+
+func BenchmarkError(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errors.Error("bench")
+	}
+}
+
+func BenchmarkErrorAtChain(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = wrapDeep(8)
+	}
+}
+
+func BenchmarkGetErrorDetails(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = errors.GetErrorDetails(wrapDeep(8))
+	}
+}
+
+func TestStackShareWithCause(t *testing.T) {
+	err := wrapDeep(3)
+
+	var layers [][]string
+	for _, line := range strings.Split(fmt.Sprintf("%+64v", err), "\n") {
+		if strings.HasPrefix(line, "* ") {
+			layers = append(layers, nil)
+		} else if strings.HasPrefix(line, "\t* ") {
+			fields := strings.Fields(line)
+			layers[len(layers)-1] = append(layers[len(layers)-1], fields[len(fields)-1])
+		}
+	}
+
+	if len(layers) != 4 {
+		t.Fatalf("unexpected layers: %v", layers)
+	}
+	for i, frames := range layers {
+		wrapDeepCount := 0
+		for _, frame := range frames {
+			if frame == "errors_test.wrapDeep" {
+				wrapDeepCount++
+			}
+		}
+		if wrapDeepCount != 4-i || frames[len(frames)-1] != "runtime.goexit" {
+			t.Fatalf("unexpected frames of layer %d: %v", i, frames)
+		}
+	}
 }
@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Field struct {
+	Key   string
+	Value any
+}
+
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+func (f Field) String() string {
+	return fmt.Sprintf("%s=%v", f.Key, f.Value)
+}
+
+// WithFields attaches fields to err.
+// If err is created by this package, the fields are added to a copy of it,
+// otherwise err is wrapped with the stack of the caller.
+func WithFields(err error, fields ...Field) error {
+	return attach(err, func(e *stackErr) {
+		e.fields = append(e.fields, fields...)
+	})
+}
+
+// Fields collects the fields along the whole unwrap chain of err,
+// from the outermost error to the innermost one.
+func Fields(err error) []Field {
+	var fields []Field
+	Walk(err, func(err error) bool {
+		if e, ok := err.(*stackErr); ok {
+			fields = append(fields, e.fields...)
+		}
+		return true
+	})
+	return fields
+}
+
+func formatFields(fields []Field) string {
+	sb := &strings.Builder{}
+	for i, f := range fields {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(f.String())
+	}
+	return sb.String()
+}
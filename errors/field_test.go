@@ -0,0 +1,61 @@
+package errors_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleWithFields() {
+	err := errors.ErrorAt(io.EOF, "read config")
+	err = errors.WithFields(err, errors.F("path", "/etc/app.conf"))
+	err = errors.ErrorAt(err, "load app")
+	err = errors.WithFields(err, errors.F("request_id", 42), errors.F("user", "bob"))
+	trimErr(err)
+
+	// Output:
+	// * EOF
+	// * read config
+	// 	* github.com/jopbrown/gobase/errors/field_test.go:12 errors_test.ExampleWithFields
+	// 	- path=/etc/app.conf
+	// * load app
+	// 	* github.com/jopbrown/gobase/errors/field_test.go:14 errors_test.ExampleWithFields
+	// 	- request_id=42 user=bob
+}
+
+func ExampleWithFields_join() {
+	err1 := errors.WithFields(errors.Error("err1"), errors.F("id", 1))
+	err2 := errors.WithFields(io.EOF, errors.F("id", 2))
+	err := errors.Join(err1, err2)
+	trimErr(err)
+
+	// Output:
+	// 1. err1
+	// 	* err1
+	// 		* github.com/jopbrown/gobase/errors/field_test.go:29 errors_test.ExampleWithFields_join
+	// 		- id=1
+	// 2. EOF
+	// 	* EOF
+	// 	* EOF
+	// 		* github.com/jopbrown/gobase/errors/field_test.go:30 errors_test.ExampleWithFields_join
+	// 		- id=2
+}
+
+func TestFields(t *testing.T) {
+	assert.Nil(t, errors.WithFields(nil, errors.F("k", "v")))
+	assert.Nil(t, errors.Fields(io.EOF))
+
+	base := errors.Error("base")
+	err := errors.WithFields(base, errors.F("a", 1))
+	assert.Empty(t, errors.Fields(base))
+	assert.Equal(t, base.Error(), err.Error())
+
+	err = errors.ErrorAt(err, "wrap")
+	err = errors.WithFields(err, errors.F("b", 2))
+	err = errors.Join(err, errors.WithFields(io.EOF, errors.F("c", 3)))
+
+	assert.Equal(t, []errors.Field{errors.F("b", 2), errors.F("a", 1), errors.F("c", 3)}, errors.Fields(err))
+	assert.True(t, errors.Is(err, io.EOF))
+}
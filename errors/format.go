@@ -0,0 +1,295 @@
+package errors
+
+import (
+	"fmt"
+	"go/build"
+	"io"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type StackStyle int
+
+const (
+	// StackStyleMultiLine prints every frame in its own line under the message.
+	StackStyleMultiLine StackStyle = iota
+	// StackStyleCompact prints the message and its frames in one line.
+	StackStyleCompact
+)
+
+type StackFormat struct {
+	Style     StackStyle
+	MaxFrames int
+
+	// TrimPrefixes are removed from the beginning of the file paths.
+	TrimPrefixes []string
+	// TrimGOPATH removes the GOPATH, module cache and GOROOT source folders from the file paths.
+	TrimGOPATH bool
+	// TrimModuleRoot replaces the folder of the file by the import path of its package.
+	TrimModuleRoot bool
+
+	// HideRuntime hides the frames of the runtime and testing packages.
+	HideRuntime bool
+	// Packages only shows the frames of the packages matched by the patterns,
+	// a pattern is matched by path.Match or is a prefix ends with "/...".
+	Packages []string
+
+	// InnermostFullStack shows all the frames of the innermost error
+	// and MaxFrames frames of the others.
+	InnermostFullStack bool
+
+	// SourceLines shows the source code around each frame in the multi-line style,
+	// it is the number of lines before and after the line of the frame, 0 disables it.
+	SourceLines int
+}
+
+func DefaultStackFormat() StackFormat {
+	return StackFormat{
+		Style:     StackStyleMultiLine,
+		MaxFrames: 1,
+	}
+}
+
+var globalStackFormat atomic.Pointer[StackFormat]
+
+func init() {
+	f := DefaultStackFormat()
+	globalStackFormat.Store(&f)
+}
+
+func GetStackFormat() StackFormat {
+	return *globalStackFormat.Load()
+}
+
+func SetStackFormat(f StackFormat) StackFormat {
+	old := globalStackFormat.Swap(&f)
+	return *old
+}
+
+func GetErrorDetailsWithFormat(err error, f StackFormat) string {
+	if err == nil {
+		return ""
+	}
+	sb := &strings.Builder{}
+	sb.WriteString("\n")
+	p := &printer{format: f, detail: true, verb: "%+v"}
+	p.print(sb, err)
+	return sb.String()
+}
+
+type printer struct {
+	format StackFormat
+	detail bool
+	// verb is used to format the errors which are not created by this package.
+	verb string
+}
+
+func newPrinter(state fmt.State, verb rune) *printer {
+	p := &printer{}
+	p.format = GetStackFormat()
+	p.detail = state.Flag('+')
+	p.verb = revertFormatState(state, verb)
+	if wid, ok := state.Width(); ok {
+		p.format.MaxFrames = wid
+	}
+	return p
+}
+
+type detailPrinter interface {
+	printDetail(w io.Writer, p *printer)
+}
+
+func (p *printer) print(w io.Writer, err error) {
+	if dp, ok := err.(detailPrinter); ok {
+		dp.printDetail(w, p)
+		return
+	}
+
+	if _, ok := err.(fmt.Formatter); ok {
+		fmt.Fprintf(w, p.verb, err)
+		return
+	}
+
+	fmt.Fprintf(w, "* %v\n", err)
+}
+
+func (e *stackErr) printDetail(w io.Writer, p *printer) {
+	if e.cause != nil {
+		p.print(w, e.cause)
+	}
+
+	fields := e.fields
+	if e.kind != KindUnknown {
+		fields = append([]Field{F("kind", e.kind)}, fields...)
+	}
+
+	if !p.detail {
+		fmt.Fprintf(w, "* %s\n", e.msg)
+		return
+	}
+
+	maxFrames := p.format.MaxFrames
+	if p.format.InnermostFullStack && causeStack(e.cause) == nil {
+		maxFrames = -1
+	}
+	frames := p.frames(e.stack.resolve(), maxFrames)
+
+	switch p.format.Style {
+	case StackStyleCompact:
+		fmt.Fprintf(w, "* %s", e.msg)
+		if len(frames) > 0 {
+			io.WriteString(w, " (")
+			for i, frame := range frames {
+				if i > 0 {
+					io.WriteString(w, ", ")
+				}
+				p.printFrame(w, frame)
+			}
+			io.WriteString(w, ")")
+		}
+		if len(fields) > 0 {
+			fmt.Fprintf(w, " {%s}", formatFields(fields))
+		}
+		io.WriteString(w, "\n")
+	default:
+		fmt.Fprintf(w, "* %s\n", e.msg)
+		for _, frame := range frames {
+			io.WriteString(w, "\t* ")
+			p.printFrame(w, frame)
+			io.WriteString(w, "\n")
+			if p.format.SourceLines > 0 {
+				p.printSource(w, frame.File, frame.Line)
+			}
+		}
+		if len(fields) > 0 {
+			fmt.Fprintf(w, "\t- %s\n", formatFields(fields))
+		}
+	}
+}
+
+func (e *multiErr) printDetail(w io.Writer, p *printer) {
+	for i, err := range e.errs {
+		_, isDetailPrinter := err.(detailPrinter)
+		_, isFormatter := err.(fmt.Formatter)
+
+		fmt.Fprintf(w, "%d. %s", i+1, rootCausesMessage(err))
+		// the fields of a detailed error are printed in its own block
+		if p.detail && !isDetailPrinter && !isFormatter {
+			if fields := Fields(err); len(fields) > 0 {
+				fmt.Fprintf(w, " {%s}", formatFields(fields))
+			}
+		}
+		io.WriteString(w, "\n")
+
+		if !isDetailPrinter && !isFormatter {
+			continue
+		}
+
+		sb := &strings.Builder{}
+		p.print(sb, err)
+		lines := strings.Split(sb.String(), "\n")
+		for _, line := range lines {
+			if line != "" {
+				fmt.Fprintf(w, "\t%s\n", line)
+			}
+		}
+	}
+}
+
+func (p *printer) frames(frames []runtime.Frame, maxFrames int) []runtime.Frame {
+	if !p.format.HideRuntime && len(p.format.Packages) == 0 {
+		if maxFrames >= 0 && len(frames) > maxFrames {
+			frames = frames[:maxFrames]
+		}
+		return frames
+	}
+
+	filtered := make([]runtime.Frame, 0, len(frames))
+	for _, frame := range frames {
+		if maxFrames >= 0 && len(filtered) >= maxFrames {
+			break
+		}
+
+		pkg := funcPackage(frame.Function)
+		if p.format.HideRuntime && (pkg == "runtime" || pkg == "testing") {
+			continue
+		}
+		if len(p.format.Packages) > 0 && !matchPackage(p.format.Packages, pkg) {
+			continue
+		}
+		filtered = append(filtered, frame)
+	}
+	return filtered
+}
+
+func (p *printer) printFrame(w io.Writer, frame runtime.Frame) {
+	fmt.Fprintf(w, "%s:%d %s", p.trimFile(frame), frame.Line, path.Base(frame.Function))
+}
+
+func (p *printer) trimFile(frame runtime.Frame) string {
+	file := frame.File
+
+	if p.format.TrimModuleRoot {
+		if pkg := funcPackage(frame.Function); pkg != "" && !strings.HasPrefix(frame.Function, "runtime.") {
+			return strings.TrimSuffix(pkg, "_test") + "/" + path.Base(file)
+		}
+	}
+
+	if p.format.TrimGOPATH {
+		for _, prefix := range goPathPrefixes() {
+			if strings.HasPrefix(file, prefix) {
+				return file[len(prefix):]
+			}
+		}
+	}
+
+	for _, prefix := range p.format.TrimPrefixes {
+		if strings.HasPrefix(file, prefix) {
+			return strings.TrimPrefix(file[len(prefix):], "/")
+		}
+	}
+
+	return file
+}
+
+var goPathPrefixes = sync.OnceValue(func() []string {
+	prefixes := make([]string, 0, 3)
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		gopath = filepath.ToSlash(gopath)
+		prefixes = append(prefixes, gopath+"/pkg/mod/", gopath+"/src/")
+	}
+	if build.Default.GOROOT != "" {
+		prefixes = append(prefixes, filepath.ToSlash(build.Default.GOROOT)+"/src/")
+	}
+	return prefixes
+})
+
+// funcPackage returns the import path of the package of a function name
+// like "github.com/user/repo/pkg.(*Type).Method".
+func funcPackage(function string) string {
+	lastSlash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[lastSlash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return function[:lastSlash+1+dot]
+}
+
+func matchPackage(patterns []string, pkg string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+			if pkg == prefix || strings.HasPrefix(pkg, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, pkg); ok {
+			return true
+		}
+	}
+	return false
+}
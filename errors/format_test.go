@@ -0,0 +1,99 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFormatTestErr() error {
+	err := errors.ErrorAt(io.EOF, "read")
+	err = errors.WithFields(err, errors.F("id", 7))
+	return errors.ErrorAt(err, "load")
+}
+
+func ExampleGetErrorDetailsWithFormat() {
+	f := errors.DefaultStackFormat()
+	f.TrimModuleRoot = true
+	fmt.Print(errors.GetErrorDetailsWithFormat(newFormatTestErr(), f))
+
+	// Output:
+	// * EOF
+	// * read
+	// 	* github.com/jopbrown/gobase/errors/format_test.go:16 errors_test.newFormatTestErr
+	// 	- id=7
+	// * load
+	// 	* github.com/jopbrown/gobase/errors/format_test.go:18 errors_test.newFormatTestErr
+}
+
+func ExampleGetErrorDetailsWithFormat_compact() {
+	f := errors.DefaultStackFormat()
+	f.Style = errors.StackStyleCompact
+	f.TrimModuleRoot = true
+	f.MaxFrames = 2
+	f.Packages = []string{"github.com/jopbrown/gobase/..."}
+	fmt.Print(errors.GetErrorDetailsWithFormat(newFormatTestErr(), f))
+
+	// Output:
+	// * EOF
+	// * read (github.com/jopbrown/gobase/errors/format_test.go:16 errors_test.newFormatTestErr, github.com/jopbrown/gobase/errors/format_test.go:41 errors_test.ExampleGetErrorDetailsWithFormat_compact) {id=7}
+	// * load (github.com/jopbrown/gobase/errors/format_test.go:18 errors_test.newFormatTestErr, github.com/jopbrown/gobase/errors/format_test.go:41 errors_test.ExampleGetErrorDetailsWithFormat_compact)
+}
+
+func ExampleSetStackFormat() {
+	f := errors.DefaultStackFormat()
+	f.TrimModuleRoot = true
+	f.Packages = []string{"github.com/jopbrown/gobase/*"}
+	f.InnermostFullStack = true
+	old := errors.SetStackFormat(f)
+	defer errors.SetStackFormat(old)
+
+	err := errors.Join(newFormatTestErr(), io.ErrUnexpectedEOF)
+	fmt.Printf("%+v", err)
+
+	// Output:
+	// 1. EOF
+	// 	* EOF
+	// 	* read
+	// 		* github.com/jopbrown/gobase/errors/format_test.go:16 errors_test.newFormatTestErr
+	// 		* github.com/jopbrown/gobase/errors/format_test.go:57 errors_test.ExampleSetStackFormat
+	// 		- id=7
+	// 	* load
+	// 		* github.com/jopbrown/gobase/errors/format_test.go:18 errors_test.newFormatTestErr
+	// 2. unexpected EOF
+}
+
+func TestStackFormatTrimPath(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	dir := path.Dir(file)
+
+	f := errors.DefaultStackFormat()
+	f.TrimPrefixes = []string{dir}
+	details := errors.GetErrorDetailsWithFormat(errors.Error("trim"), f)
+	assert.Contains(t, details, "\t* format_test.go:")
+	assert.NotContains(t, details, dir)
+
+	f = errors.DefaultStackFormat()
+	f.TrimGOPATH = true
+	f.MaxFrames = 64
+	details = errors.GetErrorDetailsWithFormat(errors.Error("trim"), f)
+	assert.Contains(t, details, "\t* testing/testing.go:")
+
+	f.HideRuntime = true
+	details = errors.GetErrorDetailsWithFormat(errors.Error("trim"), f)
+	assert.NotContains(t, details, "testing.go")
+	assert.Equal(t, 1, strings.Count(details, "\t* "))
+}
+
+func TestStackFormatWidth(t *testing.T) {
+	err := errors.Error("width")
+	assert.Equal(t, 1, strings.Count(fmt.Sprintf("%+v", err), "\t* "))
+	assert.Equal(t, 3, strings.Count(fmt.Sprintf("%+3v", err), "\t* "))
+	assert.Equal(t, 0, strings.Count(fmt.Sprintf("%v", err), "\t* "))
+}
@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// Group collects the errors of goroutines, the zero value is ready to use.
+type Group struct {
+	mu   sync.Mutex
+	errs []error
+
+	wg     sync.WaitGroup
+	sem    chan struct{}
+	cancel context.CancelCauseFunc
+}
+
+func NewGroup() *Group {
+	return &Group{}
+}
+
+// GroupWithContext returns a group which cancels the derived context when the first error is added.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &Group{}
+	g.cancel = cancel
+	return g, ctx
+}
+
+// SetLimit limits the number of running goroutines, n <= 0 means no limit.
+// It must be called before any goroutine is started.
+func (g *Group) SetLimit(n int) {
+	if n <= 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs fn in a new goroutine, the error or panic of fn is added to the group.
+func (g *Group) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+
+		var err error
+		perr := Catch(func() {
+			err = fn()
+		})
+		g.Add(err, perr)
+	}()
+}
+
+// Add appends errors to the group, it is safe to be called by multiple goroutines.
+func (g *Group) Add(errs ...error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		g.errs = append(g.errs, err)
+		if g.cancel != nil {
+			g.cancel(err)
+		}
+	}
+}
+
+func (g *Group) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.errs)
+}
+
+// Wait waits for all goroutines and returns the joined errors.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(nil)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return Join(g.errs...)
+}
@@ -0,0 +1,115 @@
+package errors_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	g := errors.NewGroup()
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			if i%3 == 0 {
+				return errors.Errorf("job %d failed", i)
+			}
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	require.Error(t, err)
+	assert.Equal(t, 4, g.Len())
+	assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 4)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "errors_test.TestGroup.func1")
+
+	assert.NoError(t, errors.NewGroup().Wait())
+}
+
+func TestGroupWaitTwice(t *testing.T) {
+	workerErr := errors.Join(errors.Error("first"), errors.Error("second"))
+	g := errors.NewGroup()
+	g.Add(workerErr)
+	g.Go(func() error { return errors.Error("third") })
+	g.Go(func() error { return nil })
+
+	err1 := g.Wait()
+	err2 := g.Wait()
+	require.Error(t, err1)
+	assert.Equal(t, err1.Error(), err2.Error())
+	assert.Equal(t, 2, errors.Count(workerErr))
+	assert.Equal(t, "first\nsecond", workerErr.Error())
+	assert.Equal(t, 2, g.Len())
+}
+
+func TestGroupPanic(t *testing.T) {
+	g := errors.NewGroup()
+	g.Go(func() error {
+		panicAt("worker crashed")
+		return nil
+	})
+	g.Add(nil, context.Canceled)
+
+	err := g.Wait()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	var found bool
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		if v, ok := errors.PanicValueAs[string](e); ok {
+			found = true
+			assert.Equal(t, "worker crashed", v)
+			assert.Contains(t, fmt.Sprintf("%+v", e), "errors_test.panicAt")
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestGroupLimit(t *testing.T) {
+	g := errors.NewGroup()
+	g.SetLimit(2)
+
+	var running, maxRunning atomic.Int32
+	for i := 0; i < 8; i++ {
+		g.Go(func() error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+	}
+
+	assert.NoError(t, g.Wait())
+	assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+}
+
+func TestGroupWithContext(t *testing.T) {
+	g, ctx := errors.GroupWithContext(context.Background())
+	failure := errors.Error("first failure")
+
+	g.Go(func() error {
+		return failure
+	})
+	g.Go(func() error {
+		<-ctx.Done()
+		return errors.ErrorAt(context.Cause(ctx), "canceled by group")
+	})
+
+	err := g.Wait()
+	require.Error(t, err)
+	assert.Equal(t, 2, g.Len())
+	assert.True(t, errors.Is(err, failure))
+	assert.ErrorIs(t, context.Cause(ctx), failure)
+}
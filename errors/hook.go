@@ -0,0 +1,130 @@
+package errors
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Hook is invoked when a new error is created by Error, Errorf, ErrorAt, ErrorAtf or WithStack,
+// frame is where the error is created.
+// A hook must not create errors by this package, otherwise it is invoked recursively.
+type Hook func(err error, frame runtime.Frame)
+
+type HookOptions struct {
+	// Every invokes the hook for one of every n errors, 0 or 1 means all of them.
+	Every int
+	// Limit is the maximum number of invocations in each Interval, 0 means unlimited.
+	Limit int
+	// Interval is the window of Limit, default is 1 second.
+	Interval time.Duration
+}
+
+type hookEntry struct {
+	hook Hook
+	opts HookOptions
+
+	seen atomic.Uint64
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowCount int
+}
+
+var (
+	hooksMu      sync.Mutex
+	hooks        atomic.Pointer[[]*hookEntry]
+	hooksEnabled atomic.Bool
+)
+
+func init() {
+	hooksEnabled.Store(true)
+}
+
+func AddHook(h Hook) (remove func()) {
+	return AddHookWithOptions(h, HookOptions{})
+}
+
+// AddHookWithOptions registers h with sampling and rate limiting, call remove to unregister it.
+func AddHookWithOptions(h Hook, opts HookOptions) (remove func()) {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	entry := &hookEntry{hook: h, opts: opts}
+
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	var entries []*hookEntry
+	if old := hooks.Load(); old != nil {
+		entries = append(entries, *old...)
+	}
+	entries = append(entries, entry)
+	hooks.Store(&entries)
+
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+
+		old := hooks.Load()
+		entries := make([]*hookEntry, 0, len(*old))
+		for _, e := range *old {
+			if e != entry {
+				entries = append(entries, e)
+			}
+		}
+		hooks.Store(&entries)
+	}
+}
+
+// SetHooksEnabled enables or disables all hooks and returns the old state,
+// it is useful to silence hooks in tests.
+func SetHooksEnabled(enabled bool) bool {
+	return hooksEnabled.Swap(enabled)
+}
+
+func runHooks(e *stackErr) {
+	entries := hooks.Load()
+	if entries == nil || len(*entries) == 0 || !hooksEnabled.Load() {
+		return
+	}
+
+	var frame runtime.Frame
+	resolved := false
+	for _, entry := range *entries {
+		if !entry.allow() {
+			continue
+		}
+		if !resolved {
+			frame = e.stack.top()
+			resolved = true
+		}
+		entry.hook(e, frame)
+	}
+}
+
+func (entry *hookEntry) allow() bool {
+	n := entry.seen.Add(1)
+	if entry.opts.Every > 1 && (n-1)%uint64(entry.opts.Every) != 0 {
+		return false
+	}
+
+	if entry.opts.Limit <= 0 {
+		return true
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	now := time.Now()
+	if entry.windowStart.IsZero() || now.Sub(entry.windowStart) >= entry.opts.Interval {
+		entry.windowStart = now
+		entry.windowCount = 0
+	}
+	if entry.windowCount >= entry.opts.Limit {
+		return false
+	}
+	entry.windowCount++
+	return true
+}
@@ -0,0 +1,74 @@
+package errors_test
+
+import (
+	"io"
+	"path"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHook(t *testing.T) {
+	var errs []error
+	var frames []runtime.Frame
+	remove := errors.AddHook(func(err error, frame runtime.Frame) {
+		errs = append(errs, err)
+		frames = append(frames, frame)
+	})
+
+	err1 := errors.Error("err1")
+	err2 := errors.ErrorAtf(io.EOF, "err%d", 2)
+	remove()
+	errors.Error("err3")
+
+	assert.Equal(t, []error{err1, err2}, errs)
+	assert.Equal(t, "errors_test.TestHook", path.Base(frames[0].Function))
+	assert.Equal(t, "errors_test.TestHook", path.Base(frames[1].Function))
+	assert.Equal(t, frames[0].Line+1, frames[1].Line)
+}
+
+func TestHookOptions(t *testing.T) {
+	every, limited := 0, 0
+	removeEvery := errors.AddHookWithOptions(func(error, runtime.Frame) { every++ }, errors.HookOptions{Every: 3})
+	removeLimited := errors.AddHookWithOptions(func(error, runtime.Frame) { limited++ }, errors.HookOptions{Limit: 2, Interval: time.Hour})
+	defer removeEvery()
+	defer removeLimited()
+
+	for i := 0; i < 10; i++ {
+		errors.Error("sampled")
+	}
+	assert.Equal(t, 4, every)
+	assert.Equal(t, 2, limited)
+
+	old := errors.SetHooksEnabled(false)
+	errors.Error("disabled")
+	errors.SetHooksEnabled(old)
+	assert.Equal(t, 4, every)
+	assert.True(t, old)
+}
+
+func TestHookInterval(t *testing.T) {
+	n := 0
+	remove := errors.AddHookWithOptions(func(error, runtime.Frame) { n++ }, errors.HookOptions{Limit: 1, Interval: 20 * time.Millisecond})
+	defer remove()
+
+	errors.Error("first")
+	errors.Error("limited")
+	time.Sleep(30 * time.Millisecond)
+	errors.Error("next interval")
+	assert.Equal(t, 2, n)
+}
+
+func TestHookDefaultInterval(t *testing.T) {
+	n := 0
+	remove := errors.AddHookWithOptions(func(error, runtime.Frame) { n++ }, errors.HookOptions{Limit: 1})
+	defer remove()
+
+	for i := 0; i < 5; i++ {
+		errors.Error("limited")
+	}
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,122 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+const multiErrSummaryID = "gobase.errors.summary"
+
+// Catalog looks up the localized format of a message ID.
+type Catalog interface {
+	Lookup(locale, id string) (format string, ok bool)
+}
+
+type msgTemplate struct {
+	id   string
+	args []any
+}
+
+// ErrorT creates an error whose message is a template: the message ID with arguments,
+// the default format is used by Error and localized by Localize.
+func ErrorT(id string, format string, a ...any) error {
+	err := WithStack(nil, 4, fmt.Sprintf(format, a...))
+	err.(*stackErr).template = &msgTemplate{id: id, args: a}
+	return err
+}
+
+func ErrorAtT(err error, id string, format string, a ...any) error {
+	err = WithStack(err, 4, fmt.Sprintf(format, a...))
+	err.(*stackErr).template = &msgTemplate{id: id, args: a}
+	return err
+}
+
+func MessageID(err error) (string, bool) {
+	if e, ok := err.(*stackErr); ok && e.template != nil {
+		return e.template.id, true
+	}
+	return "", false
+}
+
+// Localize renders err like UserMessage with every layer localized in locale,
+// the default message is used if a layer is not a template or its ID is not in the catalog.
+func Localize(err error, cat Catalog, locale string) string {
+	return userMessage(err, func(e *stackErr) string {
+		return e.localize(cat, locale)
+	}, func(n int) string {
+		if format, ok := cat.Lookup(locale, multiErrSummaryID); ok {
+			return fmt.Sprintf(format, n)
+		}
+		return fmt.Sprintf("%d errors occurred:", n)
+	})
+}
+
+func (e *stackErr) localize(cat Catalog, locale string) string {
+	if e.template == nil {
+		return e.msg
+	}
+
+	format, ok := cat.Lookup(locale, e.template.id)
+	if !ok {
+		return e.msg
+	}
+	return fmt.Sprintf(format, e.template.args...)
+}
+
+// JSONCatalog is a catalog loaded from JSON objects mapping message IDs to formats:
+//
+//	{"config.not_found": "找不到設定檔: %s"}
+type JSONCatalog struct {
+	mu      sync.RWMutex
+	locales map[string]map[string]string
+}
+
+func NewJSONCatalog() *JSONCatalog {
+	c := &JSONCatalog{}
+	c.locales = make(map[string]map[string]string)
+	return c
+}
+
+// Load merges the messages read from r into locale.
+func (c *JSONCatalog) Load(locale string, r io.Reader) error {
+	msgs := make(map[string]string)
+	err := json.NewDecoder(r).Decode(&msgs)
+	if err != nil {
+		return ErrorAt(err, "unable to decode message catalog of locale: ", locale)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.locales[locale] == nil {
+		c.locales[locale] = make(map[string]string, len(msgs))
+	}
+	for id, format := range msgs {
+		c.locales[locale][id] = format
+	}
+	return nil
+}
+
+func (c *JSONCatalog) LoadFile(locale string, fpath string) (err error) {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return ErrorAt(err, "unable to open message catalog: ", fpath)
+	}
+	defer CaptureClose(&err, f, "unable to close message catalog: ", fpath)
+
+	err = c.Load(locale, f)
+	if err != nil {
+		return ErrorAt(err, "unable to load message catalog: ", fpath)
+	}
+	return nil
+}
+
+func (c *JSONCatalog) Lookup(locale, id string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	format, ok := c.locales[locale][id]
+	return format, ok
+}
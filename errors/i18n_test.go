@@ -0,0 +1,58 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newI18nTestErr() error {
+	err := errors.ErrorAtT(io.EOF, "config.read", "unable to read config %s", "app.yaml")
+	err = errors.ErrorAt(err, "untranslated layer")
+	return errors.ErrorAtT(err, "app.start", "unable to start %[2]s (version %[1]d)", 2, "server")
+}
+
+func TestLocalize(t *testing.T) {
+	cat := errors.NewJSONCatalog()
+	require.NoError(t, cat.LoadFile("zh-TW", "testdata/zh-TW.json"))
+	require.NoError(t, cat.Load("fr", strings.NewReader(`{"config.read": "impossible de lire %s"}`)))
+
+	err := newI18nTestErr()
+	assert.Equal(t, "unable to start server (version 2)", err.Error())
+	assert.Equal(t, "unable to start server (version 2): untranslated layer: unable to read config app.yaml: EOF", errors.UserMessage(err))
+	assert.Equal(t, "無法啟動 server (版本 2): untranslated layer: 無法讀取設定檔 app.yaml: EOF", errors.Localize(err, cat, "zh-TW"))
+	assert.Equal(t, "unable to start server (version 2): untranslated layer: impossible de lire app.yaml: EOF", errors.Localize(err, cat, "fr"))
+	assert.Equal(t, errors.UserMessage(err), errors.Localize(err, cat, "de"))
+
+	id, ok := errors.MessageID(err)
+	assert.True(t, ok)
+	assert.Equal(t, "app.start", id)
+	_, ok = errors.MessageID(io.EOF)
+	assert.False(t, ok)
+
+	joined := errors.Join(errors.ErrorT("config.read", "unable to read config %s", "a.yaml"), io.EOF)
+	assert.Equal(t, "發生 2 個錯誤:\n\t* 無法讀取設定檔 a.yaml\n\t* EOF", errors.Localize(joined, cat, "zh-TW"))
+	assert.Equal(t, "2 errors occurred:\n\t* impossible de lire a.yaml\n\t* EOF", errors.Localize(joined, cat, "fr"))
+}
+
+func TestLocalizeJSON(t *testing.T) {
+	cat := errors.NewJSONCatalog()
+	require.NoError(t, cat.LoadFile("zh-TW", "testdata/zh-TW.json"))
+
+	data, err := json.Marshal(newI18nTestErr())
+	require.NoError(t, err)
+	decoded, err := errors.DecodeJSON(data)
+	require.NoError(t, err)
+	assert.Equal(t, "無法啟動 server (版本 2): untranslated layer: 無法讀取設定檔 app.yaml: EOF", errors.Localize(decoded, cat, "zh-TW"))
+}
+
+func TestJSONCatalogLoadError(t *testing.T) {
+	cat := errors.NewJSONCatalog()
+	assert.Error(t, cat.LoadFile("zh-TW", "testdata/missing.json"))
+	assert.Error(t, cat.Load("zh-TW", strings.NewReader("[")))
+}
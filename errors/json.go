@@ -0,0 +1,186 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+)
+
+type jsonErr struct {
+	Msg    string            `json:"msg,omitempty"`
+	MsgID  string            `json:"msg_id,omitempty"`
+	Args   []json.RawMessage `json:"args,omitempty"`
+	Kind   string            `json:"kind,omitempty"`
+	Fields []jsonField       `json:"fields,omitempty"`
+	Stack  []jsonFrame       `json:"stack,omitempty"`
+	Cause  *jsonErr          `json:"cause,omitempty"`
+	Errors []*jsonErr        `json:"errors,omitempty"`
+}
+
+type jsonField struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type jsonFrame struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+}
+
+// remoteErr is a decoded error which was not created by this package,
+// it keeps only the message and the cause chain of the original error.
+type remoteErr struct {
+	msg   string
+	cause error
+}
+
+func (e *remoteErr) Error() string {
+	return e.msg
+}
+
+func (e *remoteErr) Unwrap() error {
+	return e.cause
+}
+
+func EncodeJSON(err error) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(toJSONErr(err))
+}
+
+// DecodeJSON reconstructs an error tree encoded by EncodeJSON or MarshalJSON.
+func DecodeJSON(data []byte) (error, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var je *jsonErr
+	if err := dec.Decode(&je); err != nil {
+		return nil, ErrorAt(err, "unable to decode error from json")
+	}
+
+	return fromJSONErr(je), nil
+}
+
+func (e *stackErr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONErr(e))
+}
+
+func (e *multiErr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONErr(e))
+}
+
+func toJSONErr(err error) *jsonErr {
+	if err == nil {
+		return nil
+	}
+
+	je := &jsonErr{}
+	switch e := err.(type) {
+	case *stackErr:
+		je.Msg = e.msg
+		if e.template != nil {
+			je.MsgID = e.template.id
+			for _, arg := range e.template.args {
+				je.Args = append(je.Args, marshalFieldValue(arg))
+			}
+		}
+		if e.kind != KindUnknown {
+			je.Kind = e.kind.String()
+		}
+		for _, f := range e.fields {
+			je.Fields = append(je.Fields, jsonField{Key: f.Key, Value: marshalFieldValue(f.Value)})
+		}
+		frames := e.stack.resolve()
+		je.Stack = make([]jsonFrame, 0, len(frames))
+		for _, frame := range frames {
+			je.Stack = append(je.Stack, jsonFrame{File: frame.File, Line: frame.Line, Function: frame.Function})
+		}
+		je.Cause = toJSONErr(e.cause)
+	case *multiErr:
+		je.Errors = make([]*jsonErr, 0, len(e.errs))
+		for _, child := range e.errs {
+			je.Errors = append(je.Errors, toJSONErr(child))
+		}
+	default:
+		je.Msg = err.Error()
+		if u, ok := err.(interface{ Unwrap() error }); ok {
+			je.Cause = toJSONErr(u.Unwrap())
+		}
+	}
+
+	return je
+}
+
+func marshalFieldValue(v any) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	return data
+}
+
+func fromJSONErr(je *jsonErr) error {
+	if je == nil {
+		return nil
+	}
+
+	if je.Errors != nil {
+		e := &multiErr{errs: make([]error, 0, len(je.Errors))}
+		for _, child := range je.Errors {
+			if err := fromJSONErr(child); err != nil {
+				e.errs = append(e.errs, err)
+			}
+		}
+		return e
+	}
+
+	if je.Stack == nil {
+		return &remoteErr{msg: je.Msg, cause: fromJSONErr(je.Cause)}
+	}
+
+	e := &stackErr{}
+	e.msg = je.Msg
+	e.cause = fromJSONErr(je.Cause)
+	if je.MsgID != "" {
+		e.template = &msgTemplate{id: je.MsgID}
+		for _, arg := range je.Args {
+			e.template.args = append(e.template.args, unmarshalFieldValue(arg))
+		}
+	}
+	if je.Kind != "" {
+		e.kind, _ = LookupKind(je.Kind)
+	}
+	for _, f := range je.Fields {
+		e.fields = append(e.fields, F(f.Key, unmarshalFieldValue(f.Value)))
+	}
+	frames := make([]runtime.Frame, 0, len(je.Stack))
+	for _, frame := range je.Stack {
+		frames = append(frames, runtime.Frame{File: frame.File, Line: frame.Line, Function: frame.Function})
+	}
+	e.stack = newResolvedStack(frames)
+
+	return e
+}
+
+func unmarshalFieldValue(data json.RawMessage) any {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return string(data)
+	}
+
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
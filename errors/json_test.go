@@ -0,0 +1,55 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	err := errors.ErrorAt(io.EOF, "read header")
+	err = errors.WithFields(err, errors.F("path", "/tmp/a.bin"), errors.F("offset", 128))
+	err = errors.WithKind(err, errors.KindInvalidArgument)
+	err = errors.Join(err, fmt.Errorf("wrapped: %w", os.ErrClosed), errors.Errorf("job %d failed", 3))
+	err = errors.ErrorAt(err, "batch failed")
+
+	data, e := json.Marshal(err)
+	require.NoError(t, e)
+
+	decoded, e := errors.DecodeJSON(data)
+	require.NoError(t, e)
+
+	assert.Equal(t, err.Error(), decoded.Error())
+	assert.Equal(t, fmt.Sprintf("%+5v", err), fmt.Sprintf("%+5v", decoded))
+	assert.Equal(t, fmt.Sprint(errors.Fields(err)), fmt.Sprint(errors.Fields(decoded)))
+	assert.Equal(t, errors.KindInvalidArgument, errors.KindOf(decoded))
+
+	again, e := errors.EncodeJSON(decoded)
+	require.NoError(t, e)
+	assert.JSONEq(t, string(data), string(again))
+}
+
+func TestJSONForeignError(t *testing.T) {
+	data, err := errors.EncodeJSON(io.EOF)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"msg":"EOF"}`, string(data))
+
+	decoded, err := errors.DecodeJSON(data)
+	require.NoError(t, err)
+	assert.Equal(t, "EOF", decoded.Error())
+
+	data, err = errors.EncodeJSON(nil)
+	require.NoError(t, err)
+	decoded, err = errors.DecodeJSON(data)
+	require.NoError(t, err)
+	assert.Nil(t, decoded)
+
+	_, err = errors.DecodeJSON([]byte("{"))
+	assert.Error(t, err)
+}
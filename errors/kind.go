@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"sync"
+)
+
+type Kind uint32
+
+const (
+	KindUnknown Kind = iota
+	KindNotFound
+	KindInvalidArgument
+	KindAlreadyExists
+	KindPermissionDenied
+	KindTimeout
+	KindCanceled
+	KindUnavailable
+	KindUnimplemented
+	KindInternal
+)
+
+var (
+	kindMu    sync.RWMutex
+	kindNames = []string{
+		KindUnknown:          "Unknown",
+		KindNotFound:         "NotFound",
+		KindInvalidArgument:  "InvalidArgument",
+		KindAlreadyExists:    "AlreadyExists",
+		KindPermissionDenied: "PermissionDenied",
+		KindTimeout:          "Timeout",
+		KindCanceled:         "Canceled",
+		KindUnavailable:      "Unavailable",
+		KindUnimplemented:    "Unimplemented",
+		KindInternal:         "Internal",
+	}
+	name2Kind = func() map[string]Kind {
+		m := make(map[string]Kind, len(kindNames))
+		for k, name := range kindNames {
+			m[name] = Kind(k)
+		}
+		return m
+	}()
+)
+
+// RegisterKind adds a user-defined kind, it panics if the name is already registered.
+func RegisterKind(name string) Kind {
+	kindMu.Lock()
+	defer kindMu.Unlock()
+
+	if _, ok := name2Kind[name]; ok {
+		panic(Errorf("error kind already registered: %q", name))
+	}
+
+	k := Kind(len(kindNames))
+	kindNames = append(kindNames, name)
+	name2Kind[name] = k
+	return k
+}
+
+func LookupKind(name string) (Kind, bool) {
+	kindMu.RLock()
+	defer kindMu.RUnlock()
+	k, ok := name2Kind[name]
+	return k, ok
+}
+
+func (k Kind) String() string {
+	kindMu.RLock()
+	defer kindMu.RUnlock()
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return kindNames[KindUnknown]
+}
+
+func (k Kind) Error() string {
+	return k.String()
+}
+
+func WithKind(err error, kind Kind) error {
+	return attach(err, func(e *stackErr) {
+		e.kind = kind
+	})
+}
+
+// KindOf returns the first kind found by walking err depth-first,
+// so a kind set on an inner error is inherited by the errors wrapping it.
+func KindOf(err error) Kind {
+	kind := KindUnknown
+	Walk(err, func(err error) bool {
+		switch e := err.(type) {
+		case *stackErr:
+			kind = e.kind
+		case Kind:
+			kind = e
+		}
+		return kind == KindUnknown
+	})
+	return kind
+}
+
+func IsKind(err error, kind Kind) bool {
+	return Is(err, kind)
+}
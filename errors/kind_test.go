@@ -0,0 +1,69 @@
+package errors_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var KindQuotaExceeded = errors.RegisterKind("QuotaExceeded")
+
+func ExampleWithKind() {
+	err := errors.WithKind(errors.Error("user not found"), errors.KindNotFound)
+	err = errors.ErrorAt(err, "load profile")
+	trimErr(err)
+
+	// Output:
+	// * user not found
+	// 	* github.com/jopbrown/gobase/errors/kind_test.go:15 errors_test.ExampleWithKind
+	// 	- kind=NotFound
+	// * load profile
+	// 	* github.com/jopbrown/gobase/errors/kind_test.go:16 errors_test.ExampleWithKind
+}
+
+func TestKindOf(t *testing.T) {
+	assert.Equal(t, errors.KindUnknown, errors.KindOf(nil))
+	assert.Equal(t, errors.KindUnknown, errors.KindOf(io.EOF))
+
+	err := errors.WithKind(io.EOF, errors.KindTimeout)
+	err = errors.ErrorAt(err, "wrap")
+	assert.Equal(t, errors.KindTimeout, errors.KindOf(err))
+	assert.True(t, errors.Is(err, errors.KindTimeout))
+	assert.True(t, errors.IsKind(err, errors.KindTimeout))
+	assert.True(t, errors.Is(err, io.EOF))
+	assert.False(t, errors.Is(err, errors.KindNotFound))
+	assert.False(t, errors.Is(err, errors.KindUnknown))
+
+	err = errors.WithKind(err, errors.KindInternal)
+	assert.Equal(t, errors.KindInternal, errors.KindOf(err))
+
+	joined := errors.Join(os.ErrClosed, errors.WithKind(errors.Error("quota"), KindQuotaExceeded))
+	joined = errors.ErrorAt(joined)
+	assert.Equal(t, KindQuotaExceeded, errors.KindOf(joined))
+	assert.True(t, errors.Is(joined, KindQuotaExceeded))
+
+	kind, ok := errors.AsIs[errors.Kind](errors.ErrorAt(errors.KindNotFound))
+	assert.True(t, ok)
+	assert.Equal(t, errors.KindNotFound, kind)
+}
+
+func TestRegisterKind(t *testing.T) {
+	assert.Equal(t, "QuotaExceeded", KindQuotaExceeded.String())
+	assert.Equal(t, "QuotaExceeded", KindQuotaExceeded.Error())
+
+	kind, ok := errors.LookupKind("QuotaExceeded")
+	assert.True(t, ok)
+	assert.Equal(t, KindQuotaExceeded, kind)
+
+	kind, ok = errors.LookupKind("NotFound")
+	assert.True(t, ok)
+	assert.Equal(t, errors.KindNotFound, kind)
+
+	_, ok = errors.LookupKind("NoSuchKind")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { errors.RegisterKind("NotFound") })
+}
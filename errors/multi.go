@@ -10,8 +10,9 @@ type multiErr struct {
 	errs []error
 }
 
+// Join returns an error wrapping the non-nil errs, or nil if there is none.
+// The errors of a first *multiErr are flattened into a new one, errs are never modified.
 func Join(errs ...error) error {
-	n := 0
 	nonNilErrs := make([]error, 0, len(errs))
 	for _, err := range errs {
 		if err != nil {
@@ -21,19 +22,16 @@ func Join(errs ...error) error {
 	if len(nonNilErrs) == 0 {
 		return nil
 	}
-	e := &multiErr{
-		errs: make([]error, 0, n),
-	}
 
 	if e0, ok := nonNilErrs[0].(*multiErr); ok {
-		e = e0
-	} else {
-		e.errs = append(e.errs, nonNilErrs[0])
+		e := &multiErr{}
+		e.errs = make([]error, 0, len(e0.errs)+len(nonNilErrs)-1)
+		e.errs = append(e.errs, e0.errs...)
+		e.errs = append(e.errs, nonNilErrs[1:]...)
+		return e
 	}
 
-	e.errs = append(e.errs, nonNilErrs[1:]...)
-
-	return e
+	return &multiErr{errs: nonNilErrs}
 }
 
 func (e *multiErr) Error() string {
@@ -54,20 +52,7 @@ func (e *multiErr) Unwrap() []error {
 func (e *multiErr) Format(state fmt.State, verb rune) {
 	switch verb {
 	case 'v':
-		for i, err := range e.errs {
-			fmt.Fprintf(state, "%d. %s\n", i+1, RootCause(err).Error())
-			if _, ok := err.(fmt.Formatter); ok {
-				sb := &strings.Builder{}
-				format := revertFormatState(state, verb)
-				fmt.Fprintf(sb, format, err)
-				lines := strings.Split(sb.String(), "\n")
-				for _, line := range lines {
-					if line != "" {
-						fmt.Fprintf(state, "\t%s\n", line)
-					}
-				}
-			}
-		}
+		e.printDetail(state, newPrinter(state, verb))
 	case 's':
 		for _, err := range e.errs {
 			fmt.Fprintf(state, "%s\n", err.Error())
@@ -101,3 +86,94 @@ func revertFormatState(state fmt.State, verb rune) string {
 
 	return sb.String()
 }
+
+// EachLeaf returns an iterator over the errors which are not joined errors,
+// the joined errors are walked depth-first.
+// The signature is compatible with iter.Seq[error].
+func EachLeaf(err error) func(yield func(error) bool) {
+	return func(yield func(error) bool) {
+		eachLeaf(err, yield)
+	}
+}
+
+func eachLeaf(err error, yield func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	e, ok := err.(*multiErr)
+	if !ok {
+		return yield(err)
+	}
+
+	for _, child := range e.errs {
+		if !eachLeaf(child, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func Leaves(err error) []error {
+	leaves := make([]error, 0, 1)
+	EachLeaf(err)(func(leaf error) bool {
+		leaves = append(leaves, leaf)
+		return true
+	})
+	return leaves
+}
+
+func Count(err error) int {
+	n := 0
+	EachLeaf(err)(func(error) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Flatten joins the leaves of err into a single level joined error.
+func Flatten(err error) error {
+	if _, ok := err.(*multiErr); !ok {
+		return err
+	}
+	return Join(Leaves(err)...)
+}
+
+func Filter(err error, keep func(err error) bool) error {
+	leaves := make([]error, 0, 1)
+	EachLeaf(err)(func(leaf error) bool {
+		if keep(leaf) {
+			leaves = append(leaves, leaf)
+		}
+		return true
+	})
+	return Join(leaves...)
+}
+
+// DedupByMessage keeps the first leaf of each distinct message.
+func DedupByMessage(err error) error {
+	seen := make(map[string]struct{})
+	return Filter(err, func(leaf error) bool {
+		msg := leaf.Error()
+		if _, ok := seen[msg]; ok {
+			return false
+		}
+		seen[msg] = struct{}{}
+		return true
+	})
+}
+
+// DedupByIs keeps the first leaf of the leaves which are equivalent by Is.
+func DedupByIs(err error) error {
+	kept := make([]error, 0, 1)
+	return Filter(err, func(leaf error) bool {
+		for _, k := range kept {
+			if Is(leaf, k) || Is(k, leaf) {
+				return false
+			}
+		}
+		kept = append(kept, leaf)
+		return true
+	})
+}
@@ -3,8 +3,10 @@ package errors_test
 import (
 	"io"
 	"os"
+	"testing"
 
 	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
 )
 
 func ExampleJoin() {
@@ -28,25 +30,75 @@ func ExampleJoin() {
 	// 1. EOF
 	// 2. err1
 	// 	* err1
-	// 		* github.com/jopbrown/gobase/errors/multi_test.go:12 errors_test.ExampleJoin
+	// 		* github.com/jopbrown/gobase/errors/multi_test.go:14 errors_test.ExampleJoin
 	// 3. err2
 	// 	* err2
-	// 		* github.com/jopbrown/gobase/errors/multi_test.go:13 errors_test.ExampleJoin
+	// 		* github.com/jopbrown/gobase/errors/multi_test.go:15 errors_test.ExampleJoin
 	// 4. err3
 	// 	* err3
-	// 		* github.com/jopbrown/gobase/errors/multi_test.go:14 errors_test.ExampleJoin
-	// 5. err4
-	// err5
-	// file does not exist
+	// 		* github.com/jopbrown/gobase/errors/multi_test.go:16 errors_test.ExampleJoin
+	// 5. err4; err5; file does not exist
 	// 	1. err4
 	// 		* err4
-	// 			* github.com/jopbrown/gobase/errors/multi_test.go:15 errors_test.ExampleJoin
+	// 			* github.com/jopbrown/gobase/errors/multi_test.go:17 errors_test.ExampleJoin
 	// 	2. err5
 	// 		* err5
-	// 			* github.com/jopbrown/gobase/errors/multi_test.go:16 errors_test.ExampleJoin
+	// 			* github.com/jopbrown/gobase/errors/multi_test.go:18 errors_test.ExampleJoin
 	// 	3. file does not exist
 	// 6. file already closed
 	// 7. err8
 	// 	* err8
-	// 		* github.com/jopbrown/gobase/errors/multi_test.go:19 errors_test.ExampleJoin
+	// 		* github.com/jopbrown/gobase/errors/multi_test.go:21 errors_test.ExampleJoin
+}
+
+func newNestedJoin() error {
+	return errors.Join(
+		io.EOF,
+		errors.Join(os.ErrClosed, errors.Join(io.EOF, os.ErrNotExist)),
+		errors.ErrorAt(io.EOF, "read"),
+		errors.Join(os.ErrClosed),
+	)
+}
+
+func TestLeaves(t *testing.T) {
+	err := newNestedJoin()
+	leaves := errors.Leaves(err)
+	assert.Equal(t, 6, errors.Count(err))
+	assert.Len(t, leaves, 6)
+	assert.Equal(t, []error{io.EOF, os.ErrClosed, io.EOF, os.ErrNotExist}, leaves[:4])
+	assert.Equal(t, "read", leaves[4].Error())
+
+	assert.Equal(t, 1, errors.Count(io.EOF))
+	assert.Equal(t, 0, errors.Count(nil))
+
+	n := 0
+	errors.EachLeaf(err)(func(leaf error) bool {
+		n++
+		return n < 2
+	})
+	assert.Equal(t, 2, n)
+}
+
+func TestFlatten(t *testing.T) {
+	err := errors.Flatten(newNestedJoin())
+	children := err.(interface{ Unwrap() []error }).Unwrap()
+	assert.Len(t, children, 6)
+	for _, child := range children {
+		_, nested := child.(interface{ Unwrap() []error })
+		assert.False(t, nested)
+	}
+
+	assert.Equal(t, io.EOF, errors.Flatten(io.EOF))
+	assert.Nil(t, errors.Flatten(nil))
+}
+
+func TestFilterAndDedup(t *testing.T) {
+	err := newNestedJoin()
+
+	filtered := errors.Filter(err, func(leaf error) bool { return errors.Is(leaf, io.EOF) })
+	assert.Equal(t, 3, errors.Count(filtered))
+	assert.Nil(t, errors.Filter(err, func(error) bool { return false }))
+
+	assert.Equal(t, "EOF\nfile already closed\nfile does not exist\nread", errors.DedupByMessage(err).Error())
+	assert.Equal(t, "EOF\nfile already closed\nfile does not exist", errors.DedupByIs(err).Error())
 }
@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Recover converts a panic into an error by the same rule as Catch and stores it in errp.
+// It must be called by defer directly:
+//
+//	defer errors.Recover(&err)
+func Recover(errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	err := panicToError(r)
+	if *errp != nil {
+		err = Join(*errp, err)
+	}
+	*errp = err
+}
+
+// Try runs fn and converts its panic into an error.
+func Try(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+// Go runs fn in a new goroutine, the returned channel receives the error or the panic of fn.
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		ch <- Try(fn)
+	}()
+	return ch
+}
+
+// panicToError converts the recovered value r into an error for Recover and Catch.
+// An error of this package is returned as is,
+// others are wrapped with the stack of the panic site and reported by IsPanic.
+func panicToError(r any) error {
+	switch v := r.(type) {
+	case checkPanic:
+		return v.err
+	case *stackErr:
+		return v
+	}
+
+	return newPanicErr(r, getPanicStack(4))
+}
+
+func newPanicErr(r any, s *stack) error {
+	e := &stackErr{}
+	e.stack = s
+	e.panicValue = r
+	if cause, ok := r.(error); ok {
+		e.cause = cause
+		e.msg = "panic: " + cause.Error()
+	} else {
+		e.msg = fmt.Sprint(r)
+	}
+	return e
+}
+
+func PanicValue(err error) (any, bool) {
+	for err != nil {
+		if e, ok := err.(*stackErr); ok && e.panicValue != nil {
+			return e.panicValue, true
+		}
+
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return nil, false
+		}
+		err = u.Unwrap()
+	}
+	return nil, false
+}
+
+func PanicValueAs[T any](err error) (T, bool) {
+	var none T
+	r, ok := PanicValue(err)
+	if !ok {
+		return none, false
+	}
+	v, ok := r.(T)
+	return v, ok
+}
+
+func IsPanic(err error) bool {
+	_, ok := PanicValue(err)
+	return ok
+}
+
+func IsRuntimePanic(err error) bool {
+	_, ok := PanicValueAs[runtime.Error](err)
+	return ok
+}
@@ -0,0 +1,128 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panicValue struct {
+	code int
+}
+
+func panicAt(v any) {
+	panic(v)
+}
+
+func recoverPanic(v any) (err error) {
+	defer errors.Recover(&err)
+	panicAt(v)
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	err := recoverPanic(panicValue{code: 3})
+	require.Error(t, err)
+	assert.True(t, errors.IsPanic(err))
+	assert.False(t, errors.IsRuntimePanic(err))
+
+	v, ok := errors.PanicValueAs[panicValue](err)
+	assert.True(t, ok)
+	assert.Equal(t, 3, v.code)
+
+	details := fmt.Sprintf("%+v", err)
+	assert.Contains(t, details, "errors_test.panicAt")
+
+	err = recoverPanic(io.EOF)
+	assert.True(t, errors.Is(err, io.EOF))
+	assert.Equal(t, "panic: EOF", err.Error())
+
+	assert.NoError(t, errors.Try(func() error { return nil }))
+	assert.Equal(t, io.EOF, errors.Try(func() error { return io.EOF }))
+	_, ok = errors.PanicValue(io.EOF)
+	assert.False(t, ok)
+}
+
+func TestRecoverRuntimeError(t *testing.T) {
+	var m map[string]int
+	err := errors.Try(func() error {
+		m["a"] = 1
+		return nil
+	})
+	require.Error(t, err)
+	assert.True(t, errors.IsRuntimePanic(err))
+
+	rerr, ok := errors.AsIs[runtime.Error](err)
+	assert.True(t, ok)
+	assert.Contains(t, rerr.Error(), "nil map")
+
+	details := fmt.Sprintf("%+v", err)
+	assert.True(t, strings.Contains(details, "errors_test.TestRecoverRuntimeError.func1"), details)
+}
+
+func TestGo(t *testing.T) {
+	assert.NoError(t, <-errors.Go(func() error { return nil }))
+	assert.Equal(t, io.EOF, <-errors.Go(func() error { return io.EOF }))
+
+	err := <-errors.Go(func() error {
+		panicAt("boom")
+		return nil
+	})
+	v, ok := errors.PanicValueAs[string](err)
+	assert.True(t, ok)
+	assert.Equal(t, "boom", v)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "errors_test.panicAt")
+}
+
+func TestCatchPanicSite(t *testing.T) {
+	err := errors.Catch(func() { panicAt(42) })
+	v, ok := errors.PanicValueAs[int](err)
+	assert.True(t, ok)
+	assert.Equal(t, 42, v)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "errors_test.panicAt")
+}
+
+func TestRecoverAndCatchSameRule(t *testing.T) {
+	stackErr := errors.Error("stack error")
+	tests := []struct {
+		name    string
+		value   any
+		isPanic bool
+		msg     string
+	}{
+		{name: "io.EOF", value: io.EOF, isPanic: true, msg: "panic: EOF"},
+		{name: "stackErr", value: stackErr, isPanic: false, msg: "stack error"},
+		{name: "string", value: "boom", isPanic: true, msg: "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := map[string]error{
+				"Recover": recoverPanic(tt.value),
+				"Try":     errors.Try(func() error { panicAt(tt.value); return nil }),
+				"Catch":   errors.Catch(func() { panicAt(tt.value) }),
+			}
+			for helper, err := range errs {
+				require.Error(t, err, helper)
+				assert.Equal(t, tt.isPanic, errors.IsPanic(err), helper)
+				assert.Equal(t, tt.msg, err.Error(), helper)
+				if !tt.isPanic {
+					assert.Equal(t, tt.value, err, helper)
+					continue
+				}
+				v, _ := errors.PanicValue(err)
+				assert.Equal(t, tt.value, v, helper)
+				assert.Contains(t, fmt.Sprintf("%+v", err), "errors_test.panicAt", helper)
+				if e, ok := tt.value.(error); ok {
+					assert.True(t, errors.Is(err, e), helper)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jopbrown/gobase/errors"
+)
+
+type Backoff interface {
+	// Delay returns the waiting time after the n-th failed attempt, n starts from 1.
+	Delay(n int) time.Duration
+}
+
+type BackoffFunc func(n int) time.Duration
+
+func (f BackoffFunc) Delay(n int) time.Duration {
+	return f(n)
+}
+
+func Constant(d time.Duration) Backoff {
+	return BackoffFunc(func(n int) time.Duration {
+		return d
+	})
+}
+
+func Linear(initial, step, max time.Duration) Backoff {
+	return BackoffFunc(func(n int) time.Duration {
+		return capDelay(initial+time.Duration(n-1)*step, max)
+	})
+}
+
+func Exponential(initial time.Duration, multiplier float64, max time.Duration) Backoff {
+	return BackoffFunc(func(n int) time.Duration {
+		d := float64(initial) * math.Pow(multiplier, float64(n-1))
+		if d >= math.MaxInt64 {
+			return capDelay(math.MaxInt64, max)
+		}
+		return capDelay(time.Duration(d), max)
+	})
+}
+
+func capDelay(d, max time.Duration) time.Duration {
+	if max > 0 && d > max {
+		return max
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+type Clock interface {
+	Now() time.Time
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Policy decides how Do retries, the unset Backoff and stop conditions are taken from DefaultPolicy.
+type Policy struct {
+	// Backoff is the delay between attempts, the one of DefaultPolicy is used if nil.
+	Backoff Backoff
+	// Jitter randomizes each delay within [d-d*Jitter, d+d*Jitter], it should be in [0, 1].
+	Jitter float64
+	// MaxAttempts is the maximum number of attempts, 0 means unlimited if MaxElapsed is set,
+	// otherwise the one of DefaultPolicy is used.
+	MaxAttempts int
+	// MaxElapsed stops retrying if the next attempt would start after it, 0 means unlimited.
+	MaxElapsed time.Duration
+	// Retryable reports whether a failed attempt should be retried, IsRetryable is used if nil.
+	Retryable func(err error) bool
+	// MaxKeptErrors is the maximum number of the failures joined by Do, 0 means all of them.
+	// The first failure and the latest ones are kept,
+	// the one following the omitted failures has the field "omitted" with their number.
+	MaxKeptErrors int
+
+	// Clock is used to wait between attempts, the real clock is used if nil.
+	Clock Clock
+	// Rand returns a number in [0, 1) for jitter, math/rand is used if nil.
+	Rand func() float64
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		Backoff:     Exponential(100*time.Millisecond, 2, 10*time.Second),
+		Jitter:      0.2,
+		MaxAttempts: 5,
+	}
+}
+
+// IsRetryable reports false for the cancellation of context
+// and the errors classified as caused by the request itself.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	switch errors.KindOf(err) {
+	case errors.KindInvalidArgument,
+		errors.KindNotFound,
+		errors.KindAlreadyExists,
+		errors.KindPermissionDenied,
+		errors.KindUnimplemented,
+		errors.KindCanceled:
+		return false
+	}
+
+	return true
+}
+
+// Do calls fn until it succeeds or the policy stops retrying.
+// The failures of the attempts are joined and annotated with the attempt number and the delay after it,
+// at most Policy.MaxKeptErrors of them are kept.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	def := DefaultPolicy()
+	if p.Backoff == nil {
+		p.Backoff = def.Backoff
+	}
+	if p.MaxAttempts <= 0 && p.MaxElapsed <= 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+
+	clock := p.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	random := p.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+
+	start := clock.Now()
+	var errs []error
+	omitted := 0
+	addErr := func(err error) {
+		if p.MaxKeptErrors > 0 && len(errs) >= p.MaxKeptErrors {
+			omitted++
+			if len(errs) == 1 {
+				return
+			}
+			errs = append(errs[:1], errs[2:]...)
+		}
+		errs = append(errs, err)
+	}
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			addErr(errors.ErrorAt(err, "retry canceled"))
+			break
+		}
+
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		var delay time.Duration
+		stop := !retryable(err) || (p.MaxAttempts > 0 && attempt >= p.MaxAttempts)
+		if !stop {
+			delay = jitter(p.Backoff.Delay(attempt), p.Jitter, random)
+			if p.MaxElapsed > 0 && clock.Now().Add(delay).Sub(start) > p.MaxElapsed {
+				stop = true
+				delay = 0
+			}
+		}
+
+		err = errors.ErrorAtf(err, "attempt %d failed", attempt)
+		addErr(errors.WithFields(err, errors.F("attempt", attempt), errors.F("delay", delay)))
+		if stop {
+			break
+		}
+
+		if err := clock.Sleep(ctx, delay); err != nil {
+			addErr(errors.ErrorAt(err, "retry canceled"))
+			break
+		}
+	}
+
+	if omitted > 0 {
+		i := min(1, len(errs)-1)
+		errs[i] = errors.WithFields(errs[i], errors.F("omitted", omitted))
+	}
+	return errors.Join(errs...)
+}
+
+func jitter(d time.Duration, factor float64, random func() float64) time.Duration {
+	if factor <= 0 || d <= 0 {
+		return d
+	}
+	delta := float64(d) * factor
+	return time.Duration(float64(d) - delta + 2*delta*random())
+}
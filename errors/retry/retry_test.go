@@ -0,0 +1,199 @@
+package retry_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/jopbrown/gobase/errors/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return ctx.Err()
+}
+
+func failN(n int, err error) (func(ctx context.Context) error, *int) {
+	calls := 0
+	return func(ctx context.Context) error {
+		calls++
+		if calls <= n {
+			return err
+		}
+		return nil
+	}, &calls
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, retry.Constant(time.Second).Delay(5))
+
+	linear := retry.Linear(time.Second, 2*time.Second, 6*time.Second)
+	assert.Equal(t, time.Second, linear.Delay(1))
+	assert.Equal(t, 3*time.Second, linear.Delay(2))
+	assert.Equal(t, 6*time.Second, linear.Delay(4))
+
+	exp := retry.Exponential(100*time.Millisecond, 2, time.Second)
+	assert.Equal(t, 100*time.Millisecond, exp.Delay(1))
+	assert.Equal(t, 400*time.Millisecond, exp.Delay(3))
+	assert.Equal(t, time.Second, exp.Delay(10))
+	assert.Equal(t, time.Second, exp.Delay(5000))
+}
+
+func TestDo(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	p := retry.Policy{
+		Backoff:     retry.Exponential(100*time.Millisecond, 2, 0),
+		MaxAttempts: 5,
+		Clock:       clock,
+	}
+
+	fn, calls := failN(2, io.EOF)
+	assert.NoError(t, retry.Do(context.Background(), p, fn))
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, clock.sleeps)
+
+	clock.sleeps = nil
+	fn, calls = failN(10, io.EOF)
+	err := retry.Do(context.Background(), p, fn)
+	require.Error(t, err)
+	assert.Equal(t, 5, *calls)
+	assert.Equal(t, 5, errors.Count(err))
+	assert.True(t, errors.Is(err, io.EOF))
+
+	leaves := errors.Leaves(err)
+	assert.Equal(t, "attempt 1 failed", leaves[0].Error())
+	assert.Equal(t, []errors.Field{errors.F("attempt", 1), errors.F("delay", 100*time.Millisecond)}, errors.Fields(leaves[0]))
+	assert.Equal(t, []errors.Field{errors.F("attempt", 5), errors.F("delay", time.Duration(0))}, errors.Fields(leaves[4]))
+}
+
+func TestDoMaxElapsed(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	p := retry.Policy{
+		Backoff:    retry.Constant(time.Second),
+		MaxElapsed: 3500 * time.Millisecond,
+		Clock:      clock,
+	}
+
+	fn, calls := failN(10, io.EOF)
+	err := retry.Do(context.Background(), p, fn)
+	assert.Equal(t, 4, *calls)
+	assert.Equal(t, 4, errors.Count(err))
+	assert.Len(t, clock.sleeps, 3)
+}
+
+func TestDoZeroPolicy(t *testing.T) {
+	clock := &fakeClock{}
+	p := retry.Policy{Clock: clock, Rand: func() float64 { return 0.5 }}
+
+	fn, calls := failN(100, io.EOF)
+	err := retry.Do(context.Background(), p, fn)
+	def := retry.DefaultPolicy()
+	assert.Equal(t, def.MaxAttempts, *calls)
+	assert.Equal(t, def.MaxAttempts, errors.Count(err))
+	require.Len(t, clock.sleeps, def.MaxAttempts-1)
+	assert.Equal(t, def.Backoff.Delay(1), clock.sleeps[0])
+}
+
+func TestDoKeptErrors(t *testing.T) {
+	p := retry.Policy{
+		Backoff:       retry.Constant(time.Second),
+		MaxAttempts:   100,
+		MaxKeptErrors: 10,
+		Clock:         &fakeClock{},
+	}
+
+	calls := 0
+	err := retry.Do(context.Background(), p, func(ctx context.Context) error {
+		calls++
+		return errors.Errorf("failure %d", calls)
+	})
+	assert.Equal(t, 100, calls)
+	assert.Equal(t, 10, errors.Count(err))
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	require.Len(t, errs, 10)
+	assert.Equal(t, "attempt 1 failed", errs[0].Error())
+	assert.Equal(t, "attempt 92 failed", errs[1].Error())
+	assert.Equal(t, "attempt 100 failed", errs[9].Error())
+	assert.Contains(t, errors.Fields(errs[1]), errors.F("omitted", 90))
+	assert.NotContains(t, errors.Fields(errs[0]), errors.F("omitted", 90))
+
+	p.MaxKeptErrors = 1
+	err = retry.Do(context.Background(), p, func(ctx context.Context) error { return io.EOF })
+	assert.Equal(t, 1, errors.Count(err))
+	assert.Contains(t, errors.Fields(err), errors.F("omitted", 99))
+
+	p.MaxKeptErrors = 0
+	err = retry.Do(context.Background(), p, func(ctx context.Context) error { return io.EOF })
+	assert.Equal(t, 100, errors.Count(err))
+}
+
+func TestDoJitter(t *testing.T) {
+	clock := &fakeClock{}
+	p := retry.Policy{
+		Backoff:     retry.Constant(time.Second),
+		Jitter:      0.5,
+		MaxAttempts: 3,
+		Clock:       clock,
+		Rand:        func() float64 { return 0 },
+	}
+
+	fn, _ := failN(10, io.EOF)
+	retry.Do(context.Background(), p, fn)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, clock.sleeps)
+}
+
+func TestDoNotRetryable(t *testing.T) {
+	p := retry.Policy{Backoff: retry.Constant(time.Second), Clock: &fakeClock{}}
+
+	fn, calls := failN(10, errors.WithKind(io.EOF, errors.KindInvalidArgument))
+	err := retry.Do(context.Background(), p, fn)
+	assert.Equal(t, 1, *calls)
+	assert.True(t, errors.Is(err, errors.KindInvalidArgument))
+
+	p.Retryable = func(err error) bool { return !errors.Is(err, io.ErrUnexpectedEOF) }
+	fn, calls = failN(10, io.ErrUnexpectedEOF)
+	retry.Do(context.Background(), p, fn)
+	assert.Equal(t, 1, *calls)
+
+	assert.False(t, retry.IsRetryable(nil))
+	assert.False(t, retry.IsRetryable(context.Canceled))
+	assert.True(t, retry.IsRetryable(errors.WithKind(io.EOF, errors.KindTimeout)))
+}
+
+func TestDoContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := retry.Policy{Backoff: retry.Constant(time.Second), Clock: &fakeClock{}}
+
+	calls := 0
+	err := retry.Do(ctx, p, func(ctx context.Context) error {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return io.EOF
+	})
+	assert.Equal(t, 2, calls)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 3, errors.Count(err))
+}
+
+func TestDoRealClock(t *testing.T) {
+	p := retry.Policy{Backoff: retry.Constant(time.Millisecond), MaxAttempts: 3}
+	fn, calls := failN(2, io.EOF)
+	assert.NoError(t, retry.Do(context.Background(), p, fn))
+	assert.Equal(t, 3, *calls)
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var sourceCache struct {
+	mu    sync.Mutex
+	files map[string][][]byte
+}
+
+// sourceLines returns the lines of file, or nil if the file is unavailable.
+// Both results are cached.
+func sourceLines(file string) [][]byte {
+	sourceCache.mu.Lock()
+	defer sourceCache.mu.Unlock()
+
+	if lines, ok := sourceCache.files[file]; ok {
+		return lines
+	}
+	if sourceCache.files == nil {
+		sourceCache.files = make(map[string][][]byte)
+	}
+
+	var lines [][]byte
+	data, err := os.ReadFile(file)
+	if err == nil {
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+		lines = bytes.Split(data, []byte("\n"))
+	}
+	sourceCache.files[file] = lines
+	return lines
+}
+
+// printSource prints the lines around the line of frame, the line of frame is marked by ">".
+func (p *printer) printSource(w io.Writer, file string, line int) {
+	lines := sourceLines(file)
+	if line < 1 || line > len(lines) {
+		io.WriteString(w, "\t\t  (source unavailable)\n")
+		return
+	}
+
+	first := max(line-p.format.SourceLines, 1)
+	last := min(line+p.format.SourceLines, len(lines))
+	width := len(fmt.Sprint(last))
+	for i := first; i <= last; i++ {
+		mark := ' '
+		if i == line {
+			mark = '>'
+		}
+		fmt.Fprintf(w, "\t\t%c %*d | %s\n", mark, width, i, bytes.TrimRight(lines[i-1], " \t"))
+	}
+}
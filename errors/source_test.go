@@ -0,0 +1,45 @@
+package errors_test
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSourceTestErr() error {
+	n := 1
+	return errors.Errorf("failed at %d", n)
+}
+
+func ExampleStackFormat_sourceLines() {
+	f := errors.DefaultStackFormat()
+	f.TrimModuleRoot = true
+	f.SourceLines = 1
+	fmt.Print(errors.GetErrorDetailsWithFormat(newSourceTestErr(), f))
+
+	// Output:
+	// * failed at 1
+	// 	* github.com/jopbrown/gobase/errors/source_test.go:15 errors_test.newSourceTestErr
+	// 		  14 | 	n := 1
+	// 		> 15 | 	return errors.Errorf("failed at %d", n)
+	// 		  16 | }
+}
+
+func TestSourceUnavailable(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	data := []byte(fmt.Sprintf(`{"msg":"missing","stack":[{"file":"/no/such/file.go","line":3,"function":"main.main"},{"file":%q,"line":100000,"function":"main.main"}]}`, file))
+	decoded, e := errors.DecodeJSON(data)
+	require.NoError(t, e)
+
+	f := errors.DefaultStackFormat()
+	f.MaxFrames = 2
+	f.SourceLines = 2
+	assert.Equal(t, "\n* missing\n"+
+		"\t* /no/such/file.go:3 main.main\n\t\t  (source unavailable)\n"+
+		"\t* "+file+":100000 main.main\n\t\t  (source unavailable)\n",
+		errors.GetErrorDetailsWithFormat(decoded, f))
+}
@@ -0,0 +1,173 @@
+package errors
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const maxStackDepth = 64
+
+// stack keeps the raw program counters and resolves them into frames only when formatted.
+// The part shared with the stack of the cause is not stored again but referenced by parent.
+type stack struct {
+	pcs        []uintptr
+	parent     *stack
+	parentSkip int
+
+	once   sync.Once
+	frames []runtime.Frame
+}
+
+func getStack(skip int, cause error) *stack {
+	var buf [maxStackDepth]uintptr
+	n := runtime.Callers(skip, buf[:])
+
+	s := &stack{}
+	parent := causeStack(cause)
+	shared := 0
+	if parent != nil {
+		shared = parent.commonSuffix(buf[:n])
+	}
+
+	if shared > 0 {
+		s.parent = parent
+		s.parentSkip = parent.len() - shared
+	}
+	s.pcs = make([]uintptr, n-shared)
+	copy(s.pcs, buf[:n-shared])
+
+	return s
+}
+
+func newResolvedStack(frames []runtime.Frame) *stack {
+	s := &stack{}
+	s.once.Do(func() {
+		s.frames = frames
+	})
+	return s
+}
+
+func causeStack(err error) *stack {
+	for err != nil {
+		if e, ok := err.(*stackErr); ok {
+			if e.stack.isResolvedOnly() {
+				return nil
+			}
+			return e.stack
+		}
+
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return nil
+		}
+		err = u.Unwrap()
+	}
+	return nil
+}
+
+func (s *stack) isResolvedOnly() bool {
+	return s.pcs == nil && s.parent == nil
+}
+
+func (s *stack) len() int {
+	n := len(s.pcs)
+	if s.parent != nil {
+		n += s.parent.len() - s.parentSkip
+	}
+	return n
+}
+
+func (s *stack) pcAt(i int) uintptr {
+	if i < len(s.pcs) {
+		return s.pcs[i]
+	}
+	return s.parent.pcAt(s.parentSkip + i - len(s.pcs))
+}
+
+func (s *stack) commonSuffix(pcs []uintptr) int {
+	n := s.len()
+	i := 0
+	for i < n && i < len(pcs) && s.pcAt(n-1-i) == pcs[len(pcs)-1-i] {
+		i++
+	}
+	return i
+}
+
+func (s *stack) appendPCs(pcs []uintptr, from int) []uintptr {
+	if from < len(s.pcs) {
+		pcs = append(pcs, s.pcs[from:]...)
+		from = 0
+	} else {
+		from -= len(s.pcs)
+	}
+
+	if s.parent != nil {
+		pcs = s.parent.appendPCs(pcs, s.parentSkip+from)
+	}
+	return pcs
+}
+
+func (s *stack) resolve() []runtime.Frame {
+	s.once.Do(func() {
+		pcs := s.appendPCs(make([]uintptr, 0, s.len()), 0)
+		if len(pcs) == 0 {
+			return
+		}
+
+		s.frames = make([]runtime.Frame, 0, len(pcs))
+		frames := runtime.CallersFrames(pcs)
+		for {
+			frame, more := frames.Next()
+			s.frames = append(s.frames, frame)
+			if !more {
+				break
+			}
+		}
+	})
+	return s.frames
+}
+
+// getPanicStack captures the stack of a deferred function called by a panic
+// and drops the frames above the panic site.
+func getPanicStack(skip int) *stack {
+	var buf [maxStackDepth]uintptr
+	n := runtime.Callers(skip, buf[:])
+	pcs := buf[:n]
+
+	for i, pc := range pcs {
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil || fn.Name() != "runtime.gopanic" {
+			continue
+		}
+
+		site := i + 1
+		for site < len(pcs)-1 {
+			fn := runtime.FuncForPC(pcs[site] - 1)
+			if fn == nil || !strings.HasPrefix(fn.Name(), "runtime.") {
+				break
+			}
+			site++
+		}
+		pcs = pcs[site:]
+		break
+	}
+
+	s := &stack{}
+	s.pcs = make([]uintptr, len(pcs))
+	copy(s.pcs, pcs)
+	return s
+}
+
+// top resolves only the first frame of the stack.
+func (s *stack) top() runtime.Frame {
+	if s.len() == 0 {
+		if frames := s.resolve(); len(frames) > 0 {
+			return frames[0]
+		}
+		return runtime.Frame{}
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{s.pcAt(0)}).Next()
+	return frame
+}
@@ -1,14 +1,19 @@
 package errors
 
+import (
+	"fmt"
+)
+
+// Must panics with err wrapped by the stack of the caller if err is not nil.
 func Must(err error) {
 	if err != nil {
-		panic(err)
+		panic(WithStack(err, 4, ""))
 	}
 }
 
 func Must1[T any](v T, err error) T {
 	if err != nil {
-		panic(err)
+		panic(WithStack(err, 4, ""))
 	}
 
 	return v
@@ -16,7 +21,7 @@ func Must1[T any](v T, err error) T {
 
 func Must2[T1, T2 any](v1 T1, v2 T2, err error) (T1, T2) {
 	if err != nil {
-		panic(err)
+		panic(WithStack(err, 4, ""))
 	}
 
 	return v1, v2
@@ -24,22 +29,67 @@ func Must2[T1, T2 any](v1 T1, v2 T2, err error) (T1, T2) {
 
 func Must3[T1, T2, T3 any](v1 T1, v2 T2, v3 T3, err error) (T1, T2, T3) {
 	if err != nil {
-		panic(err)
+		panic(WithStack(err, 4, ""))
 	}
 
 	return v1, v2, v3
 }
 
+func MustMsg(err error, a ...any) {
+	if err != nil {
+		panic(WithStack(err, 4, fmt.Sprint(a...)))
+	}
+}
+
+func MustMsgf(err error, format string, a ...any) {
+	if err != nil {
+		panic(WithStack(err, 4, fmt.Sprintf(format, a...)))
+	}
+}
+
+// Must1Msg is the Must1 with a message:
+//
+//	cfg := errors.Must1Msg(loadConfig(path))("unable to load config: ", path)
+func Must1Msg[T any](v T, err error) func(a ...any) T {
+	return func(a ...any) T {
+		if err != nil {
+			panic(WithStack(err, 4, fmt.Sprint(a...)))
+		}
+		return v
+	}
+}
+
+func Must2Msg[T1, T2 any](v1 T1, v2 T2, err error) func(a ...any) (T1, T2) {
+	return func(a ...any) (T1, T2) {
+		if err != nil {
+			panic(WithStack(err, 4, fmt.Sprint(a...)))
+		}
+		return v1, v2
+	}
+}
+
+func Must3Msg[T1, T2, T3 any](v1 T1, v2 T2, v3 T3, err error) func(a ...any) (T1, T2, T3) {
+	return func(a ...any) (T1, T2, T3) {
+		if err != nil {
+			panic(WithStack(err, 4, fmt.Sprint(a...)))
+		}
+		return v1, v2, v3
+	}
+}
+
+// Should1 returns the value and whether the call succeeded, like the comma-ok idiom:
+//
+//	if v, ok := errors.Should1(strconv.Atoi(s)); ok { ... }
 func Should1[T any](v T, err error) (T, bool) {
-	return v, err != nil
+	return v, err == nil
 }
 
 func Should2[T1, T2 any](v1 T1, v2 T2, err error) (T1, T2, bool) {
-	return v1, v2, err != nil
+	return v1, v2, err == nil
 }
 
 func Should3[T1, T2, T3 any](v1 T1, v2 T2, v3 T3, err error) (T1, T2, T3, bool) {
-	return v1, v2, v3, err != nil
+	return v1, v2, v3, err == nil
 }
 
 func Has(err error) bool {
@@ -82,19 +132,76 @@ func Get3[T1, T2, T3 any](v1 T1, v2 T2, v3 T3, err error) error {
 	return err
 }
 
+// checkPanic is the panic value of Check, only recovered by Handle, Catch and Recover.
+type checkPanic struct {
+	err error
+}
+
+// Check panics with err wrapped by the stack of the caller if err is not nil,
+// the panic should be recovered by Handle in the same function:
+//
+//	func load(path string) (cfg *Config, err error) {
+//		defer errors.Handle(&err)
+//		data := errors.Check1(os.ReadFile(path))
+//		...
+//	}
+func Check(err error) {
+	if err != nil {
+		panic(checkPanic{WithStack(err, 4, "")})
+	}
+}
+
+func Check1[T any](v T, err error) T {
+	if err != nil {
+		panic(checkPanic{WithStack(err, 4, "")})
+	}
+	return v
+}
+
+func Check2[T1, T2 any](v1 T1, v2 T2, err error) (T1, T2) {
+	if err != nil {
+		panic(checkPanic{WithStack(err, 4, "")})
+	}
+	return v1, v2
+}
+
+func Check3[T1, T2, T3 any](v1 T1, v2 T2, v3 T3, err error) (T1, T2, T3) {
+	if err != nil {
+		panic(checkPanic{WithStack(err, 4, "")})
+	}
+	return v1, v2, v3
+}
+
+// Handle recovers the panic of Check, passes the error through handlers and stores it in errp.
+// Other panics are propagated. It must be called by defer directly.
+func Handle(errp *error, handlers ...func(err error) error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	cp, ok := r.(checkPanic)
+	if !ok {
+		panic(r)
+	}
+
+	err := cp.err
+	for _, h := range handlers {
+		err = h(err)
+	}
+	*errp = err
+}
+
+// Catch converts the panic of fn into an error.
+// A panic value which is already an error of this package is returned as is,
+// others are wrapped with the stack of the panic site and reported by IsPanic.
 func Catch(fn func()) (err error) {
 	defer func() {
 		r := recover()
 		if r == nil {
 			return
 		}
-
-		if e, ok := r.(error); ok {
-			err = e
-			return
-		}
-
-		err = Error(r)
+		err = panicToError(r)
 	}()
 	fn()
 	return
@@ -1,6 +1,7 @@
 package errors_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/jopbrown/gobase/errors"
@@ -63,16 +64,83 @@ func TestMust(t *testing.T) {
 	assert.NotPanics(t, func() { errors.Must1(NoErr1()) })
 	assert.NotPanics(t, func() { errors.Must2(NoErr2()) })
 	assert.NotPanics(t, func() { errors.Must3(NoErr3()) })
+
+	err := errors.Catch(func() { errors.Must1(Err1()) })
+	assert.True(t, errors.Is(err, baseErr))
+	assert.Contains(t, fmt.Sprintf("%+v", err), "errors_test.TestMust.func")
+}
+
+func TestMustMsg(t *testing.T) {
+	err := errors.Catch(func() { errors.MustMsg(Err(), "must msg") })
+	assert.Equal(t, "must msg", err.Error())
+	assert.True(t, errors.Is(err, baseErr))
+
+	err = errors.Catch(func() { errors.MustMsgf(Err(), "must %s", "msgf") })
+	assert.Equal(t, "must msgf", err.Error())
+
+	err = errors.Catch(func() { errors.Must1Msg(Err1())("must1") })
+	assert.Equal(t, "must1", err.Error())
+	err = errors.Catch(func() { errors.Must2Msg(Err2())("must2") })
+	assert.Equal(t, "must2", err.Error())
+	err = errors.Catch(func() { errors.Must3Msg(Err3())("must3") })
+	assert.Equal(t, "must3", err.Error())
+	assert.Contains(t, fmt.Sprintf("%+v", err), "errors_test.TestMustMsg.func")
+
+	assert.Equal(t, 1, errors.Must1Msg(NoErr1())("unused"))
+	assert.Equal(t, []any{1, 2}, tuple2Slice2(errors.Must2Msg(NoErr2())("unused")))
+	assert.Equal(t, []any{1, 2, 3}, tuple2Slice3(errors.Must3Msg(NoErr3())("unused")))
+	assert.NotPanics(t, func() { errors.MustMsg(NoErr(), "unused") })
+}
+
+func checkAll(fail int) (sum int, err error) {
+	defer errors.Handle(&err, func(err error) error {
+		return errors.ErrorAt(err, "check all")
+	})
+
+	sum += errors.Check1(NoErr1())
+	if fail == 1 {
+		errors.Check(Err())
+	}
+	v1, v2 := errors.Check2(NoErr2())
+	sum += v1 + v2
+	if fail == 3 {
+		errors.Check3(Err3())
+	}
+	return sum, nil
+}
+
+func TestCheckHandle(t *testing.T) {
+	sum, err := checkAll(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, sum)
+
+	_, err = checkAll(1)
+	assert.Equal(t, "check all", err.Error())
+	assert.True(t, errors.Is(err, baseErr))
+	assert.Contains(t, fmt.Sprintf("%+3v", err), "errors_test.checkAll")
+
+	_, err = checkAll(3)
+	assert.True(t, errors.Is(err, baseErr))
+
+	assert.PanicsWithValue(t, "other", func() {
+		var err error
+		defer errors.Handle(&err)
+		panic("other")
+	})
+
+	assert.True(t, errors.Is(errors.Catch(func() { errors.Check(Err()) }), baseErr))
+	assert.True(t, errors.Is(errors.Try(func() error { errors.Check(Err()); return nil }), baseErr))
+	assert.False(t, errors.IsPanic(errors.Try(func() error { errors.Check(Err()); return nil })))
 }
 
 func TestShould(t *testing.T) {
-	assert.Equal(t, []any{1, true}, tuple2Slice2(errors.Should1(Err1())))
-	assert.Equal(t, []any{1, 2, true}, tuple2Slice3(errors.Should2(Err2())))
-	assert.Equal(t, []any{1, 2, 3, true}, tuple2Slice4(errors.Should3(Err3())))
+	assert.Equal(t, []any{1, false}, tuple2Slice2(errors.Should1(Err1())))
+	assert.Equal(t, []any{1, 2, false}, tuple2Slice3(errors.Should2(Err2())))
+	assert.Equal(t, []any{1, 2, 3, false}, tuple2Slice4(errors.Should3(Err3())))
 
-	assert.Equal(t, []any{1, false}, tuple2Slice2(errors.Should1(NoErr1())))
-	assert.Equal(t, []any{1, 2, false}, tuple2Slice3(errors.Should2(NoErr2())))
-	assert.Equal(t, []any{1, 2, 3, false}, tuple2Slice4(errors.Should3(NoErr3())))
+	assert.Equal(t, []any{1, true}, tuple2Slice2(errors.Should1(NoErr1())))
+	assert.Equal(t, []any{1, 2, true}, tuple2Slice3(errors.Should2(NoErr2())))
+	assert.Equal(t, []any{1, 2, 3, true}, tuple2Slice4(errors.Should3(NoErr3())))
 }
 
 func TestHas(t *testing.T) {
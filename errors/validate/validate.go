@@ -0,0 +1,124 @@
+package validate
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/jopbrown/gobase/strutil"
+)
+
+// All joins the failed checks, every failure is an invalid-argument error with the stack of the check site:
+//
+//	return validate.All(
+//		validate.NotEmpty("name", name),
+//		validate.InRange("port", port, 1, 65535),
+//	)
+func All(checks ...error) error {
+	return errors.Join(checks...)
+}
+
+// Assert panics with the joined failures, it is used to check internal invariants.
+func Assert(checks ...error) {
+	if err := All(checks...); err != nil {
+		panic(err)
+	}
+}
+
+func fail(name string, format string, a ...any) error {
+	err := errors.WithStack(nil, 5, fmt.Sprintf(format, a...))
+	err = errors.WithKind(err, errors.KindInvalidArgument)
+	if name != "" {
+		err = errors.WithFields(err, errors.F("arg", name))
+	}
+	return err
+}
+
+func That(cond bool, a ...any) error {
+	if cond {
+		return nil
+	}
+	return fail("", "%s", fmt.Sprint(a...))
+}
+
+func Thatf(cond bool, format string, a ...any) error {
+	if cond {
+		return nil
+	}
+	return fail("", format, a...)
+}
+
+func NotNil(name string, v any) error {
+	if !isNil(v) {
+		return nil
+	}
+	return fail(name, "%s must not be nil", name)
+}
+
+// NotEmpty fails if v is an empty string, slice, map, array or channel, or a zero value of other types.
+func NotEmpty(name string, v any) error {
+	if !isEmpty(v) {
+		return nil
+	}
+	return fail(name, "%s must not be empty", name)
+}
+
+func InRange[T cmp.Ordered](name string, v, min, max T) error {
+	if v >= min && v <= max {
+		return nil
+	}
+	return fail(name, "%s must be in range [%v, %v] but got %v", name, min, max, v)
+}
+
+func OneOf[T comparable](name string, v T, options ...T) error {
+	for _, option := range options {
+		if v == option {
+			return nil
+		}
+	}
+	return fail(name, "%s must be one of %v but got %v", name, options, v)
+}
+
+func Matches(name string, s string, m strutil.Matcher) error {
+	if m.MatchString(s) {
+		return nil
+	}
+	return fail(name, "%s is not matched: %q", name, s)
+}
+
+// Satisfies checks v by a custom predicate, desc describes the expectation.
+func Satisfies[T any](name string, v T, pred func(v T) bool, desc string) error {
+	if pred(v) {
+		return nil
+	}
+	return fail(name, "%s must %s but got %v", name, desc, v)
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil() || isEmpty(rv.Elem().Interface())
+	}
+	return rv.IsZero()
+}
@@ -0,0 +1,115 @@
+package validate_test
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/jopbrown/gobase/errors/validate"
+	"github.com/jopbrown/gobase/strutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type config struct {
+	Name  string
+	Port  int
+	Mode  string
+	Path  string
+	Tags  []string
+	Inner *config
+}
+
+func validateConfig(cfg *config) error {
+	return validate.All(
+		validate.NotEmpty("name", cfg.Name),
+		validate.InRange("port", cfg.Port, 1, 65535),
+		validate.OneOf("mode", cfg.Mode, "dev", "prod"),
+		validate.Matches("path", cfg.Path, strutil.MustComplieGlob("*.yaml")),
+		validate.NotNil("inner", cfg.Inner),
+		validate.Satisfies("tags", cfg.Tags, func(tags []string) bool { return len(tags) <= 2 }, "have at most 2 items"),
+	)
+}
+
+func TestAll(t *testing.T) {
+	valid := &config{Name: "app", Port: 80, Mode: "dev", Path: "app.yaml", Inner: &config{}}
+	assert.NoError(t, validateConfig(valid))
+
+	invalid := &config{Port: 0, Mode: "test", Path: "app.json", Tags: []string{"a", "b", "c"}}
+	err := validateConfig(invalid)
+	assert.Equal(t, 6, errors.Count(err))
+	assert.True(t, errors.Is(err, errors.KindInvalidArgument))
+	assert.Equal(t, strings.Join([]string{
+		"name must not be empty",
+		"port must be in range [1, 65535] but got 0",
+		`mode must be one of [dev prod] but got test`,
+		`path is not matched: "app.json"`,
+		"inner must not be nil",
+		"tags must have at most 2 items but got [a b c]",
+	}, "\n"), err.Error())
+
+	_, file, _, _ := runtime.Caller(0)
+	for i, leaf := range errors.Leaves(err) {
+		assert.Equal(t, errors.KindInvalidArgument, errors.KindOf(leaf))
+		details := fmt.Sprintf("%+v", leaf)
+		assert.Contains(t, details, fmt.Sprintf("%s:%d validate_test.validateConfig", file, 26+i))
+	}
+	assert.Equal(t, []errors.Field{errors.F("arg", "name")}, errors.Fields(errors.Leaves(err)[0]))
+}
+
+func TestAllNested(t *testing.T) {
+	inner := validate.All(validate.That(false, "x"), validate.That(false, "y"))
+	outer1 := validate.All(inner, validate.That(false, "z"))
+	outer2 := validate.All(inner, validate.That(false, "w"))
+
+	assert.Equal(t, 2, errors.Count(inner))
+	assert.Equal(t, "x\ny", inner.Error())
+	assert.Equal(t, "x\ny\nz", outer1.Error())
+	assert.Equal(t, "x\ny\nw", outer2.Error())
+}
+
+func TestThat(t *testing.T) {
+	assert.NoError(t, validate.That(true, "unused"))
+	assert.NoError(t, validate.Thatf(true, "unused"))
+	assert.Equal(t, "size is 3", validate.That(false, "size is ", 3).Error())
+	assert.Equal(t, "size is 3", validate.Thatf(false, "size is %d", 3).Error())
+	assert.Empty(t, errors.Fields(validate.That(false, "no field")))
+}
+
+func TestNilAndEmpty(t *testing.T) {
+	var nilMap map[string]int
+	var nilPtr *config
+	var nilErr error
+
+	assert.Error(t, validate.NotNil("v", nil))
+	assert.Error(t, validate.NotNil("v", nilMap))
+	assert.Error(t, validate.NotNil("v", nilPtr))
+	assert.Error(t, validate.NotNil("v", nilErr))
+	assert.NoError(t, validate.NotNil("v", 0))
+	assert.NoError(t, validate.NotNil("v", map[string]int{}))
+
+	assert.Error(t, validate.NotEmpty("v", ""))
+	assert.Error(t, validate.NotEmpty("v", []int{}))
+	assert.Error(t, validate.NotEmpty("v", nilMap))
+	assert.Error(t, validate.NotEmpty("v", 0))
+	assert.Error(t, validate.NotEmpty("v", nilPtr))
+	assert.Error(t, validate.NotEmpty("v", &config{}))
+	assert.NoError(t, validate.NotEmpty("v", "a"))
+	assert.NoError(t, validate.NotEmpty("v", []int{1}))
+	assert.NoError(t, validate.NotEmpty("v", 1))
+	assert.NoError(t, validate.NotEmpty("v", &config{Name: "a"}))
+}
+
+func TestAssert(t *testing.T) {
+	assert.NotPanics(t, func() { validate.Assert(validate.InRange("n", 1, 0, 2)) })
+
+	err := errors.Catch(func() {
+		validate.Assert(validate.InRange("n", 3, 0, 2), validate.That(false, "invariant"))
+	})
+	assert.True(t, errors.Is(err, errors.KindInvalidArgument))
+
+	v, ok := errors.PanicValueAs[error](err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, errors.Count(v))
+}
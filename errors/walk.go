@@ -0,0 +1,125 @@
+package errors
+
+import (
+	"strings"
+)
+
+// Walk calls fn for err and all the errors it wraps in depth-first order,
+// following both Unwrap() error and Unwrap() []error.
+// The walk stops if fn returns false, and Walk reports whether it was completed.
+func Walk(err error, fn func(err error) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	if !fn(err) {
+		return false
+	}
+
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return Walk(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, child := range u.Unwrap() {
+			if !Walk(child, fn) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// RootCauses returns all the errors which wrap nothing in the tree of err.
+func RootCauses(err error) []error {
+	var roots []error
+	Walk(err, func(err error) bool {
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			if u.Unwrap() == nil {
+				roots = append(roots, err)
+			}
+		case interface{ Unwrap() []error }:
+			if len(u.Unwrap()) == 0 {
+				roots = append(roots, err)
+			}
+		default:
+			roots = append(roots, err)
+		}
+		return true
+	})
+	return roots
+}
+
+// FindAs returns the first error of type E in the tree of err.
+// An error is matched if it is of type E or its As(any) bool method reports true as errors.As does.
+func FindAs[E error](err error) (E, bool) {
+	var found E
+	ok := false
+	Walk(err, func(err error) bool {
+		found, ok = asNode[E](err)
+		return !ok
+	})
+	return found, ok
+}
+
+// FindAllAs returns all the errors of type E in the tree of err, which generalizes AsIs.
+// An error is matched as FindAs does.
+func FindAllAs[E error](err error) []E {
+	var all []E
+	Walk(err, func(err error) bool {
+		if e, ok := asNode[E](err); ok {
+			all = append(all, e)
+		}
+		return true
+	})
+	return all
+}
+
+// asNode matches err itself as errors.As does, the errors wrapped by err are not visited.
+func asNode[E error](err error) (E, bool) {
+	if e, ok := err.(E); ok {
+		return e, true
+	}
+
+	var target E
+	if x, ok := err.(interface{ As(any) bool }); ok && x.As(&target) {
+		return target, true
+	}
+	return target, false
+}
+
+// PathTo returns the errors from err to the first error matched by match, or nil if nothing is matched.
+func PathTo(err error, match func(err error) bool) []error {
+	if err == nil {
+		return nil
+	}
+
+	if match(err) {
+		return []error{err}
+	}
+
+	var children []error
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		children = []error{u.Unwrap()}
+	case interface{ Unwrap() []error }:
+		children = u.Unwrap()
+	}
+
+	for _, child := range children {
+		if p := PathTo(child, match); p != nil {
+			return append([]error{err}, p...)
+		}
+	}
+	return nil
+}
+
+func rootCausesMessage(err error) string {
+	roots := RootCauses(err)
+	msgs := make([]string, 0, len(roots))
+	for _, root := range roots {
+		msgs = append(msgs, root.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,107 @@
+package errors_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTreeErr() (top, custom1, custom2 error) {
+	custom1 = errors.ErrorAt(CustomError1("c1"), "wrap c1")
+	custom2 = CustomError1("c2")
+	top = errors.ErrorAt(errors.Join(
+		io.EOF,
+		custom1,
+		fmt.Errorf("std: %w", errors.Join(os.ErrClosed, custom2)),
+	), "top")
+	return
+}
+
+func TestWalk(t *testing.T) {
+	top, _, _ := newTreeErr()
+
+	var msgs []string
+	assert.True(t, errors.Walk(top, func(err error) bool {
+		msgs = append(msgs, fmt.Sprintf("%T", err))
+		return true
+	}))
+	assert.Len(t, msgs, 9)
+
+	n := 0
+	assert.False(t, errors.Walk(top, func(err error) bool {
+		n++
+		return n < 3
+	}))
+	assert.Equal(t, 3, n)
+	assert.True(t, errors.Walk(nil, nil))
+}
+
+func TestRootCauses(t *testing.T) {
+	top, _, _ := newTreeErr()
+	assert.Equal(t, []error{io.EOF, CustomError1("c1"), os.ErrClosed, CustomError1("c2")}, errors.RootCauses(top))
+	assert.Equal(t, []error{io.EOF}, errors.RootCauses(errors.ErrorAt(io.EOF)))
+	assert.Nil(t, errors.RootCauses(nil))
+}
+
+func TestFindAs(t *testing.T) {
+	top, _, _ := newTreeErr()
+
+	c, ok := errors.FindAs[CustomError1](top)
+	assert.True(t, ok)
+	assert.Equal(t, CustomError1("c1"), c)
+
+	_, ok = errors.FindAs[CustomError2](top)
+	assert.False(t, ok)
+
+	assert.Equal(t, []CustomError1{"c1", "c2"}, errors.FindAllAs[CustomError1](top))
+	assert.Empty(t, errors.FindAllAs[CustomError2](top))
+}
+
+// asCustomError2 is converted to CustomError2 by its As method.
+type asCustomError2 struct {
+	msg string
+}
+
+func (err asCustomError2) Error() string {
+	return err.msg
+}
+
+func (err asCustomError2) As(target any) bool {
+	if p, ok := target.(*CustomError2); ok {
+		*p = CustomError2(err.msg)
+		return true
+	}
+	return false
+}
+
+func TestFindAsMethod(t *testing.T) {
+	err := errors.Join(asCustomError2{"a"}, errors.ErrorAt(asCustomError2{"b"}, "wrap"))
+
+	c, ok := errors.FindAs[CustomError2](err)
+	assert.True(t, ok)
+	assert.Equal(t, CustomError2("a"), c)
+	assert.Equal(t, []CustomError2{"a", "b"}, errors.FindAllAs[CustomError2](err))
+
+	asIs, _ := errors.AsIs[CustomError2](err)
+	assert.Equal(t, asIs, c)
+}
+
+func TestPathTo(t *testing.T) {
+	top, custom1, custom2 := newTreeErr()
+
+	path := errors.PathTo(top, func(err error) bool { return err == custom2 })
+	assert.Len(t, path, 5)
+	assert.Equal(t, top, path[0])
+	assert.True(t, errors.Is(path[2], os.ErrClosed))
+	assert.Equal(t, 2, errors.Count(path[3]))
+	assert.Equal(t, custom2, path[4])
+
+	path = errors.PathTo(top, func(err error) bool { return err == custom1 })
+	assert.Len(t, path, 3)
+
+	assert.Nil(t, errors.PathTo(top, func(err error) bool { return err == io.ErrUnexpectedEOF }))
+}
@@ -48,9 +48,9 @@ func OpenFileAppend(elem ...string) (*os.File, error) {
 	return f, nil
 }
 
-func FileTouch(elem ...string) error {
+func FileTouch(elem ...string) (err error) {
 	fname := filepath.Join(elem...)
-	err := os.MkdirAll(filepath.Dir(fname), 0755)
+	err = os.MkdirAll(filepath.Dir(fname), 0755)
 	if err != nil {
 		return errors.ErrorAt(err, "unable to create folder of file: ", fname)
 	}
@@ -62,11 +62,12 @@ func FileTouch(elem ...string) error {
 			return errors.ErrorAt(err, "unable to change modify time of file: ", fname)
 		}
 	} else {
-		f, err := os.OpenFile(fname, os.O_WRONLY|os.O_CREATE, 0666)
+		var f *os.File
+		f, err = os.OpenFile(fname, os.O_WRONLY|os.O_CREATE, 0666)
 		if err != nil {
 			return errors.ErrorAt(err, "unable to open file: ", fname)
 		}
-		f.Close()
+		defer errors.CaptureClose(&err, f, "unable to close file: ", fname)
 	}
 
 	return nil
@@ -51,7 +51,7 @@ func Copy(dst, src string) error {
 	return nil
 }
 
-func CopyFile(dst, src string) error {
+func CopyFile(dst, src string) (err error) {
 	srcInfo, err := os.Stat(src)
 	if err != nil {
 		return errors.ErrorAt(err, "src not exist: ", src)
@@ -72,13 +72,13 @@ func CopyFile(dst, src string) error {
 		return errors.ErrorAt(err, "unable to open src: ", src)
 	}
 
-	defer fin.Close()
+	defer errors.CaptureClose(&err, fin, "unable to close src: ", src)
 
 	fout, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, srcInfo.Mode().Perm())
 	if err != nil {
 		return errors.ErrorAt(err, "unable to open dst: ", dst)
 	}
-	defer fout.Close()
+	defer errors.CaptureClose(&err, fout, "unable to close dst: ", dst)
 
 	_, err = io.Copy(fout, fin)
 	if err != nil {
@@ -89,6 +89,7 @@ func CopyFile(dst, src string) error {
 }
 
 func CopyDir(dstDir, srcDir string) error {
+	var copyErr error
 	err := filepath.Walk(srcDir, func(path string, info fs.FileInfo, err error) error {
 		if srcDir == path {
 			return nil
@@ -100,9 +101,9 @@ func CopyDir(dstDir, srcDir string) error {
 		}
 		dst := filepath.Join(dstDir, relsrc)
 		if ExistsDir(src) {
-			CopyDir(dst, src)
+			copyErr = errors.Join(copyErr, CopyDir(dst, src))
 		} else {
-			CopyFile(dst, src)
+			copyErr = errors.Join(copyErr, CopyFile(dst, src))
 		}
 		return nil
 	})
@@ -111,6 +112,10 @@ func CopyDir(dstDir, srcDir string) error {
 		return errors.ErrorAt(err)
 	}
 
+	if copyErr != nil {
+		return errors.ErrorAt(copyErr, "unable to copy folder: ", srcDir)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,35 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	require.NoError(t, os.WriteFile(src, []byte("content"), 0644))
+
+	require.NoError(t, CopyFile(filepath.Join(dir, "out")+"/", src))
+	data, err := os.ReadFile(filepath.Join(dir, "out", "src.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+
+	assert.Error(t, CopyFile(filepath.Join(dir, "dst.txt"), filepath.Join(dir, "missing.txt")))
+}
+
+func TestCopyDirReportsErrors(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	require.NoError(t, FileTouch(src, "a.txt"))
+
+	// the destination collides with an existing file
+	dst := filepath.Join(dir, "dst")
+	require.NoError(t, os.WriteFile(dst, nil, 0644))
+
+	assert.Error(t, CopyDir(dst, src))
+}
@@ -0,0 +1,310 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"log/slog"
+
+	"github.com/jopbrown/gobase/errors"
+)
+
+// LevelWriter is implemented by the writers which need the level of the records,
+// Logger calls WriteLevel instead of Write if its output implements it.
+type LevelWriter interface {
+	io.Writer
+	WriteLevel(level Level, p []byte) (n int, err error)
+}
+
+// OverflowPolicy decides what AsyncWriter does with a record when the queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits until the queue has room.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the record being written.
+	OverflowDropNewest
+	// OverflowDropOldest drops the oldest queued record to make room.
+	OverflowDropOldest
+	// OverflowDropBelowLevel drops the record being written if its level is below AsyncOptions.DropLevel,
+	// otherwise waits until the queue has room.
+	OverflowDropBelowLevel
+)
+
+type AsyncOptions struct {
+	QueueSize int
+	Overflow  OverflowPolicy
+	DropLevel Level
+	// FlushInterval is the period to flush the output, 0 disables the periodic flush.
+	FlushInterval time.Duration
+	// DropReport formats the record reporting the dropped records,
+	// it is written to the output once the queue is drained.
+	// It must match the encoding of the loggers writing to the AsyncWriter,
+	// the default one is a text line, use DropReportWithFormat for JSON or logfmt.
+	DropReport func(dropped uint64) []byte
+}
+
+func DefaultAsyncOptions() AsyncOptions {
+	return AsyncOptions{
+		QueueSize:     1024,
+		Overflow:      OverflowBlock,
+		DropLevel:     LevelWarn,
+		FlushInterval: time.Second,
+		DropReport:    defaultDropReport,
+	}
+}
+
+func defaultDropReport(dropped uint64) []byte {
+	return fmt.Appendf(nil, "%-5s log: dropped %d records\n", LevelWarn, dropped)
+}
+
+// DropReportWithFormat returns a DropReport which encodes the report as a warning record by format,
+// so it matches the records of the loggers using the same format.
+func DropReportWithFormat(format LoggerFormat) func(dropped uint64) []byte {
+	return func(dropped uint64) []byte {
+		buf := &bytes.Buffer{}
+		l := NewLoggerWithFormat(buf, LevelAll, LevelAll, format)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.writeRecord(time.Now(), LevelWarn, nil, "log: dropped records", []slog.Attr{slog.Uint64("dropped", dropped)})
+		return buf.Bytes()
+	}
+}
+
+type asyncRecord struct {
+	level Level
+	p     []byte
+}
+
+// AsyncWriter queues the writes and writes them to out in a background goroutine,
+// so the loggers writing to it do not wait for a slow output.
+// The output is flushed by its Flush() error or Sync() error method if it has one.
+type AsyncWriter struct {
+	out  io.Writer
+	opts AsyncOptions
+
+	mu       sync.RWMutex
+	closed   bool
+	queue    chan asyncRecord
+	flushReq chan chan error
+	done     chan struct{}
+
+	errMu sync.Mutex
+	err   error
+
+	dropped    atomic.Uint64
+	unreported atomic.Uint64
+}
+
+func NewAsyncWriter(out io.Writer, opts AsyncOptions) *AsyncWriter {
+	def := DefaultAsyncOptions()
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = def.QueueSize
+	}
+	if opts.DropReport == nil {
+		opts.DropReport = def.DropReport
+	}
+
+	w := &AsyncWriter{}
+	w.out = out
+	w.opts = opts
+	w.queue = make(chan asyncRecord, opts.QueueSize)
+	w.flushReq = make(chan chan error)
+	w.done = make(chan struct{})
+	go w.run()
+	return w
+}
+
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(LevelAll, p)
+}
+
+func (w *AsyncWriter) WriteLevel(level Level, p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, errors.ErrorAt(os.ErrClosed, "async log writer is closed")
+	}
+
+	rec := asyncRecord{level: level, p: append([]byte(nil), p...)}
+
+	switch w.opts.Overflow {
+	case OverflowDropNewest:
+		select {
+		case w.queue <- rec:
+		default:
+			w.drop()
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case w.queue <- rec:
+				return len(p), nil
+			default:
+			}
+			select {
+			case <-w.queue:
+				w.drop()
+			default:
+			}
+		}
+	case OverflowDropBelowLevel:
+		if level < w.opts.DropLevel {
+			select {
+			case w.queue <- rec:
+			default:
+				w.drop()
+			}
+		} else {
+			w.queue <- rec
+		}
+	default:
+		w.queue <- rec
+	}
+
+	return len(p), nil
+}
+
+func (w *AsyncWriter) drop() {
+	w.dropped.Add(1)
+	w.unreported.Add(1)
+}
+
+// Dropped returns the number of the records dropped since w was created.
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Flush waits until the queued records are written and flushes the output,
+// it returns the errors occurred while writing since the last Flush.
+func (w *AsyncWriter) Flush() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return errors.ErrorAt(os.ErrClosed, "async log writer is closed")
+	}
+
+	done := make(chan error)
+	w.flushReq <- done
+	return <-done
+}
+
+// Close stops accepting records, writes the queued records and flushes the output.
+// The output is not closed.
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	return w.takeErr()
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	var tick <-chan time.Time
+	if w.opts.FlushInterval > 0 {
+		ticker := time.NewTicker(w.opts.FlushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case rec, ok := <-w.queue:
+			if !ok {
+				w.reportDropped()
+				w.setErr(w.flushOut())
+				return
+			}
+			w.write(rec.p)
+			if len(w.queue) == 0 {
+				w.reportDropped()
+			}
+		case done := <-w.flushReq:
+			w.drain()
+			w.reportDropped()
+			w.setErr(w.flushOut())
+			done <- w.takeErr()
+		case <-tick:
+			w.reportDropped()
+			w.setErr(w.flushOut())
+		}
+	}
+}
+
+func (w *AsyncWriter) drain() {
+	for {
+		select {
+		case rec, ok := <-w.queue:
+			if !ok {
+				return
+			}
+			w.write(rec.p)
+		default:
+			return
+		}
+	}
+}
+
+func (w *AsyncWriter) write(p []byte) {
+	_, err := w.out.Write(p)
+	if err != nil {
+		w.setErr(errors.ErrorAt(err))
+	}
+}
+
+func (w *AsyncWriter) reportDropped() {
+	n := w.unreported.Swap(0)
+	if n == 0 {
+		return
+	}
+	w.write(w.opts.DropReport(n))
+}
+
+func (w *AsyncWriter) flushOut() error {
+	var err error
+	switch out := w.out.(type) {
+	case interface{ Flush() error }:
+		err = out.Flush()
+	case interface{ Sync() error }:
+		err = out.Sync()
+		// the terminals and pipes can not be synced
+		if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTSUP) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return errors.ErrorAt(err)
+	}
+	return nil
+}
+
+func (w *AsyncWriter) setErr(err error) {
+	if err == nil {
+		return
+	}
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+	w.err = errors.Join(w.err, err)
+}
+
+func (w *AsyncWriter) takeErr() error {
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+	err := w.err
+	w.err = nil
+	return err
+}
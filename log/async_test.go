@@ -0,0 +1,187 @@
+package log_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/jopbrown/gobase/log"
+	"github.com/stretchr/testify/require"
+)
+
+// gateWriter blocks the first write until the gate is opened.
+type gateWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	started chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+	flushed int
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{started: make(chan struct{}), gate: make(chan struct{})}
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() {
+		close(w.started)
+		<-w.gate
+	})
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gateWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushed++
+	return nil
+}
+
+func (w *gateWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func newBlockedAsyncLogger(t *testing.T, opts log.AsyncOptions) (*log.Logger, *log.AsyncWriter, *gateWriter) {
+	out := newGateWriter()
+	w := log.NewAsyncWriter(out, opts)
+	l := log.NewLoggerWithFormat(w, log.LevelDebug, log.LevelFatal, log.SimpleLoggerFormat())
+	log.SetGlobalVerbose(0)
+
+	l.Info("first")
+	<-out.started
+	return l, w, out
+}
+
+func TestAsyncWriterDropNewest(t *testing.T) {
+	l, w, out := newBlockedAsyncLogger(t, log.AsyncOptions{QueueSize: 2, Overflow: log.OverflowDropNewest})
+	for i := 0; i < 5; i++ {
+		l.Infof("msg%d", i)
+	}
+	require.EqualValues(t, 3, w.Dropped())
+
+	close(out.gate)
+	require.NoError(t, w.Flush())
+	require.Equal(t, "first\nmsg0\nmsg1\nWARN  log: dropped 3 records\n", out.String())
+	require.NoError(t, w.Close())
+}
+
+func TestAsyncWriterDropOldest(t *testing.T) {
+	l, w, out := newBlockedAsyncLogger(t, log.AsyncOptions{QueueSize: 2, Overflow: log.OverflowDropOldest})
+	for i := 0; i < 5; i++ {
+		l.Infof("msg%d", i)
+	}
+	require.EqualValues(t, 3, w.Dropped())
+
+	close(out.gate)
+	require.NoError(t, w.Close())
+	require.Equal(t, "first\nmsg3\nmsg4\nWARN  log: dropped 3 records\n", out.String())
+}
+
+func TestAsyncWriterDropReportWithFormat(t *testing.T) {
+	format := log.JSONLoggerFormat()
+	format.AddDateTime = false
+	l, w, out := newBlockedAsyncLogger(t, log.AsyncOptions{
+		QueueSize:  1,
+		Overflow:   log.OverflowDropNewest,
+		DropReport: log.DropReportWithFormat(format),
+	})
+	l.Info("msg0")
+	l.Info("msg1")
+
+	close(out.gate)
+	require.NoError(t, w.Close())
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Equal(t, `{"level":"WARN","verbose":0,"msg":"log: dropped records","dropped":1}`, lines[len(lines)-1])
+
+	format.Encoding = log.EncodingLogfmt
+	report := log.DropReportWithFormat(format)(2)
+	require.Equal(t, "level=WARN verbose=0 msg=\"log: dropped records\" dropped=2\n", string(report))
+}
+
+func TestAsyncWriterDropBelowLevel(t *testing.T) {
+	opts := log.AsyncOptions{
+		QueueSize:  1,
+		Overflow:   log.OverflowDropBelowLevel,
+		DropLevel:  log.LevelWarn,
+		DropReport: func(n uint64) []byte { return fmt.Appendf(nil, "dropped=%d\n", n) },
+	}
+	l, w, out := newBlockedAsyncLogger(t, opts)
+	l.Info("info0")
+	l.Debug("debug")
+	l.Info("info1")
+
+	blocked := make(chan struct{})
+	go func() {
+		defer close(blocked)
+		l.Error("error")
+	}()
+
+	close(out.gate)
+	<-blocked
+	require.NoError(t, w.Close())
+	require.EqualValues(t, 2, w.Dropped())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.ElementsMatch(t, []string{"first", "info0", "error", "dropped=2"}, lines)
+}
+
+func TestAsyncWriterClose(t *testing.T) {
+	out := newGateWriter()
+	close(out.gate)
+	w := log.NewAsyncWriter(out, log.DefaultAsyncOptions())
+	l := log.NewLoggerWithFormat(w, log.LevelDebug, log.LevelFatal, log.SimpleLoggerFormat())
+	log.SetGlobalVerbose(0)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Infow("msg", "i", i, "j", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+	require.Equal(t, 1000, strings.Count(out.String(), "\n"))
+	require.Equal(t, 1, out.flushed)
+
+	_, err := w.Write([]byte("closed\n"))
+	require.True(t, errors.Is(err, os.ErrClosed))
+	require.True(t, errors.Is(w.Flush(), os.ErrClosed))
+}
+
+func ExampleAsyncWriter() {
+	errW := log.NewAsyncWriter(os.Stdout, log.DefaultAsyncOptions())
+	defer errW.Close()
+	otherBuf := bytes.NewBuffer(nil)
+	otherW := log.NewAsyncWriter(otherBuf, log.DefaultAsyncOptions())
+	defer otherW.Close()
+
+	errLog := log.NewLoggerWithFormat(errW, log.LevelWarn, log.LevelFatal, log.TestLoggerFormat())
+	otherLog := log.NewLoggerWithFormat(otherW, log.LevelDebug, log.LevelInfo, log.TestLoggerFormat())
+	log.SetGlobalLogger(log.NewTeeLogger(errLog, otherLog))
+	log.SetGlobalVerbose(0)
+
+	log.Info("Info")
+	log.Warn("Warn")
+	errW.Flush()
+	otherW.Flush()
+	fmt.Print(otherBuf.String())
+
+	// Output:
+	// WARN  V0 log_test.ExampleAsyncWriter Warn
+	// INFO  V0 log_test.ExampleAsyncWriter Info
+}
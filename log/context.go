@@ -0,0 +1,68 @@
+package log
+
+import (
+	"context"
+
+	"log/slog"
+)
+
+const (
+	TraceIDKey   = "trace_id"
+	RequestIDKey = "request_id"
+)
+
+type loggerCtxKey struct{}
+
+type attrsCtxKey struct{}
+
+// NewContext returns a copy of ctx which carries l.
+func NewContext(ctx context.Context, l ILogger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or the global logger if there is none.
+func FromContext(ctx context.Context) ILogger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerCtxKey{}).(ILogger); ok {
+			return l
+		}
+	}
+	return globalLogger
+}
+
+// ContextWithAttrs returns a copy of ctx which carries the attrs built from kv
+// in addition to the ones already carried by ctx,
+// the attrs are added to every record logged with ctx.
+func ContextWithAttrs(ctx context.Context, kv ...any) context.Context {
+	attrs := argsToAttrs(kv)
+	if len(attrs) == 0 {
+		return ctx
+	}
+	old := contextAttrs(ctx)
+	return context.WithValue(ctx, attrsCtxKey{}, append(old[:len(old):len(old)], attrs...))
+}
+
+func ContextWithTraceID(ctx context.Context, id string) context.Context {
+	return ContextWithAttrs(ctx, TraceIDKey, id)
+}
+
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return ContextWithAttrs(ctx, RequestIDKey, id)
+}
+
+func contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsCtxKey{}).([]slog.Attr)
+	return attrs
+}
+
+// contextArgsToAttrs returns the attrs carried by ctx followed by the attrs built from kv.
+func contextArgsToAttrs(ctx context.Context, kv []any) []slog.Attr {
+	attrs := contextAttrs(ctx)
+	if len(kv) == 0 {
+		return attrs
+	}
+	return append(attrs[:len(attrs):len(attrs)], argsToAttrs(kv)...)
+}
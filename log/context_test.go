@@ -0,0 +1,69 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jopbrown/gobase/log"
+)
+
+func ExampleFromContext() {
+	log.SetGlobalLogger(log.NewLoggerWithFormat(os.Stdout, log.LevelInfo, log.LevelFatal, log.TestLoggerFormat()))
+	log.SetGlobalVerbose(0)
+
+	ctx := context.Background()
+	log.FromContext(ctx).Info("global")
+
+	ctx = log.NewContext(ctx, log.With("db").WithFields("table", "users"))
+	log.FromContext(ctx).Info("from context")
+
+	// Output:
+	// INFO  V0 log_test.ExampleFromContext global
+	// INFO  V0 log_test.ExampleFromContext db from context table=users
+}
+
+func ExampleInfoContext() {
+	log.SetGlobalLogger(log.NewLoggerWithFormat(os.Stdout, log.LevelInfo, log.LevelFatal, log.TestLoggerFormat()))
+	log.SetGlobalVerbose(0)
+
+	ctx := log.ContextWithTraceID(context.Background(), "t-1")
+	ctx = log.ContextWithRequestID(ctx, "r-2")
+	log.InfoContext(ctx, "handled", "status", 200)
+
+	ctx = log.NewContext(ctx, log.With("http").WithFields("method", "GET"))
+	log.DebugContext(ctx, "hidden")
+	log.WarnContext(ctx, "slow")
+	log.FromContext(ctx).ErrorContext(ctx, "failed", "status", 500)
+
+	// Output:
+	// INFO  V0 log_test.ExampleInfoContext handled trace_id=t-1 request_id=r-2 status=200
+	// WARN  V0 log_test.ExampleInfoContext http slow method=GET trace_id=t-1 request_id=r-2
+	// ERROR V0 log_test.ExampleInfoContext http failed method=GET trace_id=t-1 request_id=r-2 status=500
+}
+
+func ExampleContextWithAttrs() {
+	l1buf := bytes.NewBuffer(nil)
+	l2buf := bytes.NewBuffer(nil)
+	l1 := log.NewLoggerWithFormat(l1buf, log.LevelDebug, log.LevelInfo, log.TestLoggerFormat())
+	l2 := log.NewLoggerWithFormat(l2buf, log.LevelWarn, log.LevelFatal, log.TestLoggerFormat())
+	log.SetGlobalLogger(log.NewTeeLogger(l1, l2))
+	log.SetGlobalVerbose(0)
+
+	ctx := log.ContextWithAttrs(context.Background(), "user", "bob")
+	s := log.WithFields("svc", "api").S(false)
+	s.InfoContext(ctx, "Info", "k", 1)
+	s.WithGroup("G").WarnContext(ctx, "Warn", "k", 2)
+	log.S(true).ErrorContext(ctx, "Error")
+
+	fmt.Print("logger1:\n", l1buf.String())
+	fmt.Print("logger2:\n", l2buf.String())
+
+	// Output:
+	// logger1:
+	// INFO  V0 log_test.ExampleContextWithAttrs Info svc=api user=bob k=1
+	// logger2:
+	// WARN  V0 log_test.ExampleContextWithAttrs Warn svc=api user=bob G.k=2
+	// {"level":"ERROR","msg":"Error","user":"bob"}
+}
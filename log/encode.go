@@ -0,0 +1,125 @@
+package log
+
+import (
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"log/slog"
+)
+
+func (l *Logger) appendJSONRecord(now time.Time, level Level, frame *runtime.Frame, msg string, attrs []slog.Attr) {
+	names := l.format.FieldNames.orDefault()
+	l.buf = append(l.buf, '{')
+	first := true
+	key := func(k string) {
+		if !first {
+			l.buf = append(l.buf, ',')
+		}
+		first = false
+		l.buf = appendJSONString(l.buf, k)
+		l.buf = append(l.buf, ':')
+	}
+
+	if l.format.AddDateTime {
+		if layout := l.format.DateTimeFormat.layout(); layout != "" {
+			key(names.Time)
+			l.buf = append(l.buf, '"')
+			l.buf = now.AppendFormat(l.buf, layout)
+			l.buf = append(l.buf, '"')
+		}
+	}
+
+	if l.format.AddLevel {
+		key(names.Level)
+		l.buf = appendJSONString(l.buf, level.String())
+	}
+
+	if l.format.AddVerbose {
+		key(names.Verbose)
+		l.buf = strconv.AppendInt(l.buf, int64(l.verbose), 10)
+	}
+
+	if frame != nil {
+		if l.format.AddSource {
+			key(names.Source)
+			l.buf = appendJSONString(l.buf, l.sourceString(frame))
+		}
+		if l.format.AddCaller {
+			key(names.Caller)
+			l.buf = appendJSONString(l.buf, l.callerString(frame))
+		}
+	}
+
+	if l.format.AddPrefix && len(l.prefix) > 0 {
+		key(names.Prefix)
+		l.buf = appendJSONString(l.buf, l.prefix)
+	}
+
+	key(names.Message)
+	l.buf = appendJSONString(l.buf, strings.TrimSuffix(msg, "\n"))
+
+	for _, a := range attrs {
+		l.buf = appendJSONAttr(l.buf, a, true)
+	}
+	l.buf = append(l.buf, '}', '\n')
+}
+
+func (l *Logger) appendLogfmtRecord(now time.Time, level Level, frame *runtime.Frame, msg string, attrs []slog.Attr) {
+	names := l.format.FieldNames.orDefault()
+	field := func(k, v string) {
+		if len(l.buf) > 0 {
+			l.buf = append(l.buf, ' ')
+		}
+		l.buf = appendTextString(l.buf, k)
+		l.buf = append(l.buf, '=')
+		l.buf = appendTextString(l.buf, v)
+	}
+
+	if l.format.AddDateTime {
+		if layout := l.format.DateTimeFormat.layout(); layout != "" {
+			field(names.Time, now.Format(layout))
+		}
+	}
+
+	if l.format.AddLevel {
+		field(names.Level, level.String())
+	}
+
+	if l.format.AddVerbose {
+		field(names.Verbose, strconv.Itoa(l.verbose))
+	}
+
+	if frame != nil {
+		if l.format.AddSource {
+			field(names.Source, l.sourceString(frame))
+		}
+		if l.format.AddCaller {
+			field(names.Caller, l.callerString(frame))
+		}
+	}
+
+	if l.format.AddPrefix && len(l.prefix) > 0 {
+		field(names.Prefix, l.prefix)
+	}
+
+	field(names.Message, strings.TrimSuffix(msg, "\n"))
+
+	for _, a := range attrs {
+		l.buf = appendTextAttr(l.buf, "", a)
+	}
+	l.buf = append(l.buf, '\n')
+}
+
+func (l *Logger) sourceString(frame *runtime.Frame) string {
+	return trimSourceFile(frame.File, l.format.SourceDepth) + ":" + strconv.Itoa(frame.Line)
+}
+
+func (l *Logger) callerString(frame *runtime.Frame) string {
+	if l.format.LongCaller {
+		return frame.Function
+	}
+	return path.Base(frame.Function)
+}
@@ -0,0 +1,62 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"log/slog"
+
+	"github.com/jopbrown/gobase/log"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleEncodingJSON() {
+	format := log.TestLoggerFormat()
+	format.Encoding = log.EncodingJSON
+	l := log.NewLoggerWithFormat(os.Stdout, log.LevelDebug, log.LevelFatal, format)
+	log.SetGlobalVerbose(0)
+
+	l.With("db").Infow("query\n", "sql", "select \"x\"", "rows", 2, slog.Group("g", "ok", true, slog.Group("empty")))
+	l.S(false).WithGroup("req").Warn("slow", "elapsed", 1500*time.Millisecond)
+
+	// Output:
+	// {"level":"INFO","verbose":0,"caller":"log_test.ExampleEncodingJSON","prefix":"db","msg":"query","sql":"select \"x\"","rows":2,"g":{"ok":true}}
+	// {"level":"WARN","verbose":0,"caller":"log_test.ExampleEncodingJSON","msg":"slow","req":{"elapsed":"1.5s"}}
+}
+
+func ExampleEncodingLogfmt() {
+	format := log.TestLoggerFormat()
+	format.Encoding = log.EncodingLogfmt
+	format.FieldNames = log.LoggerFieldNames{Level: "lvl", Caller: "func", Message: "message"}
+	l := log.NewLoggerWithFormat(os.Stdout, log.LevelDebug, log.LevelFatal, format)
+	log.SetGlobalVerbose(1)
+
+	l.V(1).With("http").WithFields("path", "/a b").Error("request failed")
+
+	// Output:
+	// lvl=ERROR verbose=1 func=log_test.ExampleEncodingLogfmt prefix=http message="request failed" path="/a b"
+}
+
+func TestJSONLoggerFormat(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := log.NewLoggerWithFormat(buf, log.LevelDebug, log.LevelFatal, log.JSONLoggerFormat())
+	log.SetGlobalVerbose(0)
+
+	l.Infow("line1\nline2\ttab", "err", os.ErrNotExist, "nan", 0.0, "any", map[string]int{"a": 1})
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "INFO", m["level"])
+	require.Equal(t, "line1\nline2\ttab", m["msg"])
+	require.Equal(t, "file does not exist", m["err"])
+	require.Equal(t, map[string]any{"a": float64(1)}, m["any"])
+	require.Contains(t, m["source"], "log/encode_test.go:")
+	require.Equal(t, "log_test.TestJSONLoggerFormat", m["caller"])
+
+	tm, err := time.Parse("2006-01-02T15:04:05.000000Z07:00", m["time"].(string))
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), tm, time.Minute)
+}
@@ -1,6 +1,8 @@
 package log
 
 type LoggerFormat struct {
+	Encoding       Encoding
+	FieldNames     LoggerFieldNames
 	AddLevel       bool
 	AddVerbose     bool
 	AddPrefix      bool
@@ -18,6 +20,89 @@ type LoggerFormatDateTime struct {
 	AddMicroseconds bool
 }
 
+// Encoding is the layout of the records written by Logger.
+type Encoding int
+
+const (
+	// EncodingText writes the fields separated by space, the attrs are appended as key=value.
+	EncodingText Encoding = iota
+	// EncodingJSON writes a JSON object per line.
+	EncodingJSON
+	// EncodingLogfmt writes key=value pairs per line.
+	EncodingLogfmt
+)
+
+// LoggerFieldNames are the keys of the fields for EncodingJSON and EncodingLogfmt,
+// an empty name falls back to the one of DefaultLoggerFieldNames.
+type LoggerFieldNames struct {
+	Level   string
+	Time    string
+	Source  string
+	Caller  string
+	Prefix  string
+	Verbose string
+	Message string
+}
+
+func DefaultLoggerFieldNames() LoggerFieldNames {
+	return LoggerFieldNames{
+		Level:   "level",
+		Time:    "time",
+		Source:  "source",
+		Caller:  "caller",
+		Prefix:  "prefix",
+		Verbose: "verbose",
+		Message: "msg",
+	}
+}
+
+func (names LoggerFieldNames) orDefault() LoggerFieldNames {
+	def := DefaultLoggerFieldNames()
+	if names.Level == "" {
+		names.Level = def.Level
+	}
+	if names.Time == "" {
+		names.Time = def.Time
+	}
+	if names.Source == "" {
+		names.Source = def.Source
+	}
+	if names.Caller == "" {
+		names.Caller = def.Caller
+	}
+	if names.Prefix == "" {
+		names.Prefix = def.Prefix
+	}
+	if names.Verbose == "" {
+		names.Verbose = def.Verbose
+	}
+	if names.Message == "" {
+		names.Message = def.Message
+	}
+	return names
+}
+
+// layout returns the time layout of the enabled parts for EncodingJSON and EncodingLogfmt.
+func (f LoggerFormatDateTime) layout() string {
+	layout := ""
+	if f.AddDate {
+		layout = "2006-01-02"
+	}
+	if f.AddTime {
+		if layout != "" {
+			layout += "T"
+		}
+		layout += "15:04:05"
+		if f.AddMicroseconds {
+			layout += ".000000"
+		}
+		if f.AddDate {
+			layout += "Z07:00"
+		}
+	}
+	return layout
+}
+
 func DefaultLoggerFormat() LoggerFormat {
 	return LoggerFormat{
 		AddLevel:    true,
@@ -107,3 +192,19 @@ func FullLoggerFormat() LoggerFormat {
 		},
 	}
 }
+
+// JSONLoggerFormat is FileLoggerFormat written as JSON lines.
+func JSONLoggerFormat() LoggerFormat {
+	format := FileLoggerFormat()
+	format.Encoding = EncodingJSON
+	format.FieldNames = DefaultLoggerFieldNames()
+	return format
+}
+
+// LogfmtLoggerFormat is FileLoggerFormat written as logfmt.
+func LogfmtLoggerFormat() LoggerFormat {
+	format := FileLoggerFormat()
+	format.Encoding = EncodingLogfmt
+	format.FieldNames = DefaultLoggerFieldNames()
+	return format
+}
@@ -1,6 +1,7 @@
 package log
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -13,11 +14,14 @@ import (
 
 type ILogger interface {
 	enabled(level Level) bool
-	output(calldepth int, level Level, msg string) error
+	allows(level Level, widen bool) bool
+	lowestLevel() Level
+	output(calldepth int, level Level, template, msg string, attrs []slog.Attr) error
 
 	GetWriter(level Level) io.Writer
 	V(v int) ILogger
 	With(prefix string) ILogger
+	WithFields(kv ...any) ILogger
 	S(json bool) *slog.Logger
 
 	Print(a ...any)
@@ -32,6 +36,14 @@ type ILogger interface {
 	Warnf(format string, a ...any)
 	Error(a ...any)
 	Errorf(format string, a ...any)
+	Debugw(msg string, kv ...any)
+	Infow(msg string, kv ...any)
+	Warnw(msg string, kv ...any)
+	Errorw(msg string, kv ...any)
+	DebugContext(ctx context.Context, msg string, kv ...any)
+	InfoContext(ctx context.Context, msg string, kv ...any)
+	WarnContext(ctx context.Context, msg string, kv ...any)
+	ErrorContext(ctx context.Context, msg string, kv ...any)
 	ErrorAt(err error, a ...any) error
 	ErrorAtf(err error, format string, a ...any) error
 	Fatal(a ...any)
@@ -96,6 +108,10 @@ func With(prefix string) ILogger {
 	return globalLogger.With(prefix)
 }
 
+func WithFields(kv ...any) ILogger {
+	return globalLogger.WithFields(kv...)
+}
+
 func S(json bool) *slog.Logger {
 	return globalLogger.S(json)
 }
@@ -121,7 +137,7 @@ func Debug(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	globalLogger.output(3, LevelDebug, msg)
+	globalLogger.output(3, LevelDebug, "", msg, nil)
 }
 
 func Debugf(format string, a ...any) {
@@ -129,7 +145,7 @@ func Debugf(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	globalLogger.output(3, LevelDebug, msg)
+	globalLogger.output(3, LevelDebug, format, msg, nil)
 }
 
 func Info(a ...any) {
@@ -137,7 +153,7 @@ func Info(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	globalLogger.output(3, LevelInfo, msg)
+	globalLogger.output(3, LevelInfo, "", msg, nil)
 }
 
 func Infof(format string, a ...any) {
@@ -145,7 +161,7 @@ func Infof(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	globalLogger.output(3, LevelInfo, msg)
+	globalLogger.output(3, LevelInfo, format, msg, nil)
 }
 
 func Warn(a ...any) {
@@ -153,7 +169,7 @@ func Warn(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	globalLogger.output(3, LevelWarn, msg)
+	globalLogger.output(3, LevelWarn, "", msg, nil)
 }
 
 func Warnf(format string, a ...any) {
@@ -161,7 +177,7 @@ func Warnf(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	globalLogger.output(3, LevelWarn, msg)
+	globalLogger.output(3, LevelWarn, format, msg, nil)
 }
 
 func Error(a ...any) {
@@ -169,7 +185,7 @@ func Error(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	globalLogger.output(3, LevelError, msg)
+	globalLogger.output(3, LevelError, "", msg, nil)
 }
 
 func Errorf(format string, a ...any) {
@@ -177,7 +193,71 @@ func Errorf(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	globalLogger.output(3, LevelError, msg)
+	globalLogger.output(3, LevelError, format, msg, nil)
+}
+
+func Debugw(msg string, kv ...any) {
+	if !globalLogger.enabled(LevelDebug) {
+		return
+	}
+	globalLogger.output(3, LevelDebug, msg, msg, argsToAttrs(kv))
+}
+
+func Infow(msg string, kv ...any) {
+	if !globalLogger.enabled(LevelInfo) {
+		return
+	}
+	globalLogger.output(3, LevelInfo, msg, msg, argsToAttrs(kv))
+}
+
+func Warnw(msg string, kv ...any) {
+	if !globalLogger.enabled(LevelWarn) {
+		return
+	}
+	globalLogger.output(3, LevelWarn, msg, msg, argsToAttrs(kv))
+}
+
+func Errorw(msg string, kv ...any) {
+	if !globalLogger.enabled(LevelError) {
+		return
+	}
+	globalLogger.output(3, LevelError, msg, msg, argsToAttrs(kv))
+}
+
+// DebugContext logs by the logger carried by ctx with the attrs carried by ctx.
+func DebugContext(ctx context.Context, msg string, kv ...any) {
+	l := FromContext(ctx)
+	if !l.enabled(LevelDebug) {
+		return
+	}
+	l.output(3, LevelDebug, msg, msg, contextArgsToAttrs(ctx, kv))
+}
+
+// InfoContext logs by the logger carried by ctx with the attrs carried by ctx.
+func InfoContext(ctx context.Context, msg string, kv ...any) {
+	l := FromContext(ctx)
+	if !l.enabled(LevelInfo) {
+		return
+	}
+	l.output(3, LevelInfo, msg, msg, contextArgsToAttrs(ctx, kv))
+}
+
+// WarnContext logs by the logger carried by ctx with the attrs carried by ctx.
+func WarnContext(ctx context.Context, msg string, kv ...any) {
+	l := FromContext(ctx)
+	if !l.enabled(LevelWarn) {
+		return
+	}
+	l.output(3, LevelWarn, msg, msg, contextArgsToAttrs(ctx, kv))
+}
+
+// ErrorContext logs by the logger carried by ctx with the attrs carried by ctx.
+func ErrorContext(ctx context.Context, msg string, kv ...any) {
+	l := FromContext(ctx)
+	if !l.enabled(LevelError) {
+		return
+	}
+	l.output(3, LevelError, msg, msg, contextArgsToAttrs(ctx, kv))
 }
 
 func ErrorAt(err error, a ...any) error {
@@ -189,7 +269,7 @@ func ErrorAt(err error, a ...any) error {
 	if !globalLogger.enabled(LevelError) {
 		return err
 	}
-	globalLogger.output(3, LevelError, errors.GetErrorDetails(err))
+	globalLogger.output(3, LevelError, "", errors.GetErrorDetails(err), nil)
 	return err
 }
 
@@ -202,14 +282,14 @@ func ErrorAtf(err error, format string, a ...any) error {
 	if !globalLogger.enabled(LevelError) {
 		return err
 	}
-	globalLogger.output(3, LevelError, errors.GetErrorDetails(err))
+	globalLogger.output(3, LevelError, format, errors.GetErrorDetails(err), nil)
 	return err
 }
 
 func Fatal(a ...any) {
 	if globalLogger.enabled(LevelFatal) {
 		msg := fmt.Sprint(a...)
-		globalLogger.output(3, LevelFatal, msg)
+		globalLogger.output(3, LevelFatal, "", msg, nil)
 	}
 	os.Exit(1)
 }
@@ -217,7 +297,7 @@ func Fatal(a ...any) {
 func Fatalf(format string, a ...any) {
 	if globalLogger.enabled(LevelFatal) {
 		msg := fmt.Sprintf(format, a...)
-		globalLogger.output(3, LevelFatal, msg)
+		globalLogger.output(3, LevelFatal, format, msg, nil)
 	}
 	os.Exit(1)
 }
@@ -225,7 +305,7 @@ func Fatalf(format string, a ...any) {
 func Panic(a ...any) {
 	msg := fmt.Sprint(a...)
 	if globalLogger.enabled(LevelPanic) {
-		globalLogger.output(3, LevelPanic, msg)
+		globalLogger.output(3, LevelPanic, "", msg, nil)
 	}
 	panic(msg)
 }
@@ -233,7 +313,7 @@ func Panic(a ...any) {
 func Panicf(format string, a ...any) {
 	msg := fmt.Sprintf(format, a...)
 	if globalLogger.enabled(LevelPanic) {
-		globalLogger.output(3, LevelPanic, msg)
+		globalLogger.output(3, LevelPanic, format, msg, nil)
 	}
 	panic(msg)
 }
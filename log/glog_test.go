@@ -256,3 +256,46 @@ func ExampleSlogWithGroup() {
 	// {"level":"WARN","msg":"Warn","GROUP":{"with":"something","attrString":"WarnString"}}
 	// {"level":"ERROR","msg":"Error","GROUP":{"with":"something","attrString":"ErrorString"}}
 }
+
+func ExampleInfow() {
+	log.SetGlobalLogger(log.NewLoggerWithFormat(os.Stdout, log.LevelInfo, log.LevelFatal, log.TestLoggerFormat()))
+	log.SetGlobalVerbose(0)
+
+	log.Debugw("Debugw", "key", "value")
+	log.Infow("Infow", "key", "value", "count", 3)
+	log.Warnw("Warnw\n", slog.Group("req", "method", "GET", "path", "/a b"))
+	log.Errorw("Errorw", "err", os.ErrNotExist, "missing")
+
+	// Output:
+	// INFO  V0 log_test.ExampleInfow Infow key=value count=3
+	// WARN  V0 log_test.ExampleInfow Warnw req.method=GET req.path="/a b"
+	// ERROR V0 log_test.ExampleInfow Errorw err="file does not exist" !BADKEY=missing
+}
+
+func ExampleWithFields() {
+	l1buf := bytes.NewBuffer(nil)
+	l2buf := bytes.NewBuffer(nil)
+	l1 := log.NewLoggerWithFormat(l1buf, log.LevelDebug, log.LevelInfo, log.TestLoggerFormat())
+	l2 := log.NewLoggerWithFormat(l2buf, log.LevelWarn, log.LevelFatal, log.TestLoggerFormat())
+	log.SetGlobalLogger(log.NewTeeLogger(l1, l2))
+	log.SetGlobalVerbose(0)
+
+	db := log.With("db").WithFields("table", "users")
+	db.Info("Info")
+	db.Infow("Infow", "rows", 2)
+	db.WithFields("id", 7).Warnf("%s", "Warnf")
+	db.S(false).Error("Error", "op", "insert")
+	db.S(true).Error("Error", "op", "insert")
+
+	fmt.Print("logger1:\n", l1buf.String())
+	fmt.Print("logger2:\n", l2buf.String())
+
+	// Output:
+	// logger1:
+	// INFO  V0 log_test.ExampleWithFields db Info table=users
+	// INFO  V0 log_test.ExampleWithFields db Infow table=users rows=2
+	// logger2:
+	// WARN  V0 log_test.ExampleWithFields db Warnf table=users id=7
+	// ERROR V0 log_test.ExampleWithFields db Error table=users op=insert
+	// {"level":"ERROR","msg":"Error","prefix":"db","table":"users","op":"insert"}
+}
@@ -0,0 +1,142 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/jopbrown/gobase/errors"
+)
+
+// LevelState is the JSON body served by LevelHandler,
+// the omitted fields are left unchanged by PUT and POST,
+// and the prefix with null level is removed from the registry.
+type LevelState struct {
+	Verbose  *int              `json:"verbose,omitempty"`
+	VModule  *string           `json:"vmodule,omitempty"`
+	MinLevel *Level            `json:"min_level,omitempty"`
+	MaxLevel *Level            `json:"max_level,omitempty"`
+	Prefixes map[string]*Level `json:"prefixes,omitempty"`
+}
+
+type levelSetter interface {
+	Levels() (minLevel, maxLevel Level)
+	SetLevels(minLevel, maxLevel Level)
+}
+
+type levelHandler struct {
+	l ILogger
+}
+
+// LevelHandler returns a http.Handler which reads the levels by GET and changes them by PUT or POST,
+// they are the global verbose, the rules of SetVModule, the levels of l and the levels registered by SetPrefixLevel.
+// The global logger is used if l is nil.
+func LevelHandler(l ILogger) http.Handler {
+	return &levelHandler{l: l}
+}
+
+func (h *levelHandler) logger() ILogger {
+	if h.l != nil {
+		return h.l
+	}
+	return globalLogger
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		err := h.update(r)
+		if err != nil {
+			writeLevelError(w, http.StatusBadRequest, err)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		writeLevelError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.state())
+}
+
+func (h *levelHandler) state() *LevelState {
+	state := &LevelState{}
+	verbose := int(globalVerbose.Load())
+	state.Verbose = &verbose
+	if vmodule := VModule(); vmodule != "" {
+		state.VModule = &vmodule
+	}
+
+	if ls, ok := h.logger().(levelSetter); ok {
+		minLevel, maxLevel := ls.Levels()
+		state.MinLevel = &minLevel
+		state.MaxLevel = &maxLevel
+	}
+
+	state.Prefixes = make(map[string]*Level)
+	for prefix, level := range PrefixLevels() {
+		state.Prefixes[prefix] = &level
+	}
+	return state
+}
+
+func (h *levelHandler) update(r *http.Request) error {
+	req := &LevelState{}
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(req)
+	if err != nil {
+		return errors.ErrorAt(err)
+	}
+
+	if req.VModule != nil {
+		_, err = parseVModule(*req.VModule)
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.MinLevel != nil || req.MaxLevel != nil {
+		ls, ok := h.logger().(levelSetter)
+		if !ok {
+			return errors.Error("the levels of the logger can not be changed")
+		}
+
+		minLevel, maxLevel := ls.Levels()
+		if req.MinLevel != nil {
+			minLevel = *req.MinLevel
+		}
+		if req.MaxLevel != nil {
+			maxLevel = *req.MaxLevel
+		}
+		if minLevel > maxLevel {
+			return errors.Errorf("min level %s is greater than max level %s", minLevel, maxLevel)
+		}
+		ls.SetLevels(minLevel, maxLevel)
+	}
+
+	if req.VModule != nil {
+		SetVModule(*req.VModule)
+	}
+
+	if req.Verbose != nil {
+		SetGlobalVerbose(*req.Verbose)
+	}
+
+	for prefix, level := range req.Prefixes {
+		if level == nil {
+			RemovePrefixLevel(prefix)
+		} else {
+			SetPrefixLevel(prefix, *level)
+		}
+	}
+
+	return nil
+}
+
+func writeLevelError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
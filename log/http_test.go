@@ -0,0 +1,94 @@
+package log_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jopbrown/gobase/log"
+	"github.com/stretchr/testify/require"
+)
+
+func doLevelRequest(t *testing.T, srv *httptest.Server, method, body string) (int, map[string]any) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	m := map[string]any{}
+	require.NoError(t, json.Unmarshal(data, &m), string(data))
+	return resp.StatusCode, m
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer log.ResetPrefixLevels()
+	defer log.SetGlobalVerbose(0)
+
+	l := log.NewLogger(io.Discard, log.LevelInfo, log.LevelFatal)
+	srv := httptest.NewServer(log.LevelHandler(l))
+	defer srv.Close()
+	log.SetGlobalVerbose(0)
+
+	code, m := doLevelRequest(t, srv, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, map[string]any{"verbose": float64(0), "min_level": "INFO", "max_level": "FATAL"}, m)
+
+	code, m = doLevelRequest(t, srv, http.MethodPut, `{"verbose":2,"min_level":"DEBUG","prefixes":{"db/sql":"DEBUG","http":"WARN"}}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, map[string]any{
+		"verbose":   float64(2),
+		"min_level": "DEBUG",
+		"max_level": "FATAL",
+		"prefixes":  map[string]any{"db/sql": "DEBUG", "http": "WARN"},
+	}, m)
+	minLevel, _ := l.Levels()
+	require.Equal(t, log.LevelDebug, minLevel)
+	require.Equal(t, 2, log.SetGlobalVerbose(2))
+
+	code, m = doLevelRequest(t, srv, http.MethodPost, `{"prefixes":{"http":null}}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, map[string]any{"db/sql": "DEBUG"}, m["prefixes"])
+
+	code, m = doLevelRequest(t, srv, http.MethodPut, `{"min_level":"VERBOSE"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Contains(t, m["error"], "unable to parse level string")
+
+	code, m = doLevelRequest(t, srv, http.MethodPut, `{"min_level":"ERROR","max_level":"WARN"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, "min level ERROR is greater than max level WARN", m["error"])
+
+	code, _ = doLevelRequest(t, srv, http.MethodDelete, "")
+	require.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestLevelHandlerGlobalLogger(t *testing.T) {
+	log.SetGlobalLogger(log.NewTeeLogger(log.NewLogger(io.Discard, log.LevelInfo, log.LevelFatal)))
+	srv := httptest.NewServer(log.LevelHandler(nil))
+	defer srv.Close()
+
+	code, m := doLevelRequest(t, srv, http.MethodPut, `{"max_level":"ERROR"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "NONE", m["min_level"])
+	require.Equal(t, "ERROR", m["max_level"])
+}
+
+func TestLevelHandlerVModule(t *testing.T) {
+	defer log.SetVModule("")
+	srv := httptest.NewServer(log.LevelHandler(log.NewLogger(io.Discard, log.LevelInfo, log.LevelFatal)))
+	defer srv.Close()
+
+	code, m := doLevelRequest(t, srv, http.MethodPut, `{"vmodule":"db/*=3"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "db/*=3", m["vmodule"])
+
+	code, _ = doLevelRequest(t, srv, http.MethodPut, `{"vmodule":"db","min_level":"DEBUG"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, "db/*=3", log.VModule())
+}
@@ -1,7 +1,10 @@
 package log
 
 import (
+	"path"
 	"strconv"
+	"sync"
+	"sync/atomic"
 
 	"log/slog"
 
@@ -79,3 +82,134 @@ func (l Level) MarshalText() ([]byte, error) {
 func (l *Level) UnmarshalText(data []byte) error {
 	return l.parse(string(data))
 }
+
+// levelRange is the enabled levels shared by a root logger and all the clones made from it or its clones.
+type levelRange struct {
+	min atomic.Int64
+	max atomic.Int64
+}
+
+func newLevelRange(minLevel, maxLevel Level) *levelRange {
+	r := &levelRange{}
+	r.set(minLevel, maxLevel)
+	return r
+}
+
+func (r *levelRange) get() (minLevel, maxLevel Level) {
+	return Level(r.min.Load()), Level(r.max.Load())
+}
+
+func (r *levelRange) set(minLevel, maxLevel Level) {
+	r.min.Store(int64(minLevel))
+	r.max.Store(int64(maxLevel))
+}
+
+// Levels returns the range of the enabled levels of l.
+func (l *Logger) Levels() (minLevel, maxLevel Level) {
+	return l.levels.get()
+}
+
+// SetLevels changes the range of the enabled levels of the whole logger tree l belongs to,
+// the range is shared by the root logger created by NewLogger and every clone made by V, With and WithFields but not Clone,
+// so calling it on a clone changes the root and the other clones as well.
+// Use SetPrefixLevel to change the level of the loggers with a prefix only.
+func (l *Logger) SetLevels(minLevel, maxLevel Level) {
+	l.levels.set(minLevel, maxLevel)
+}
+
+// Levels returns the range of the levels passed to the loggers of tee,
+// it is from LevelNone to LevelAll by default.
+func (tee *TeeLogger) Levels() (minLevel, maxLevel Level) {
+	return tee.levels.get()
+}
+
+// SetLevels changes the range of the levels passed to the loggers of tee,
+// the range is shared by tee and all its clones, so calling it on a clone changes tee as well.
+// The loggers still filter the records by their own levels.
+func (tee *TeeLogger) SetLevels(minLevel, maxLevel Level) {
+	tee.levels.set(minLevel, maxLevel)
+}
+
+var prefixLevels = struct {
+	mu     sync.RWMutex
+	levels map[string]Level
+	gen    atomic.Uint64
+}{levels: make(map[string]Level)}
+
+type prefixLevelCache struct {
+	gen   uint64
+	level Level
+	ok    bool
+}
+
+// SetPrefixLevel replaces the min level of the loggers with prefix or the sub prefixes of it,
+// the prefix is the one built by With, e.g. "db" matches "db" and "db/sql".
+// The most specific prefix wins.
+// Within a TeeLogger, a level lower than the min levels is passed only to the loggers with the lowest min level,
+// so the loggers of level ranges like ConsoleLogger are not widened to write the same record.
+func SetPrefixLevel(prefix string, level Level) {
+	prefixLevels.mu.Lock()
+	defer prefixLevels.mu.Unlock()
+	prefixLevels.levels[path.Clean(prefix)] = level
+	prefixLevels.gen.Add(1)
+}
+
+func RemovePrefixLevel(prefix string) {
+	prefixLevels.mu.Lock()
+	defer prefixLevels.mu.Unlock()
+	delete(prefixLevels.levels, path.Clean(prefix))
+	prefixLevels.gen.Add(1)
+}
+
+func ResetPrefixLevels() {
+	prefixLevels.mu.Lock()
+	defer prefixLevels.mu.Unlock()
+	prefixLevels.levels = make(map[string]Level)
+	prefixLevels.gen.Add(1)
+}
+
+func PrefixLevels() map[string]Level {
+	prefixLevels.mu.RLock()
+	defer prefixLevels.mu.RUnlock()
+	levels := make(map[string]Level, len(prefixLevels.levels))
+	for prefix, level := range prefixLevels.levels {
+		levels[prefix] = level
+	}
+	return levels
+}
+
+func lookupPrefixLevel(prefix string) (Level, bool) {
+	prefixLevels.mu.RLock()
+	defer prefixLevels.mu.RUnlock()
+	if len(prefixLevels.levels) == 0 || prefix == "" {
+		return 0, false
+	}
+
+	for {
+		if level, ok := prefixLevels.levels[prefix]; ok {
+			return level, true
+		}
+		parent := path.Dir(prefix)
+		if parent == prefix || parent == "." || parent == "/" {
+			return 0, false
+		}
+		prefix = parent
+	}
+}
+
+// prefixLevel returns the level registered for the prefix of l,
+// the result is cached until the registry is changed.
+func (l *Logger) prefixLevel() (Level, bool) {
+	gen := prefixLevels.gen.Load()
+	if gen == 0 {
+		return 0, false
+	}
+
+	if c := l.prefixLevelCache.Load(); c != nil && c.gen == gen {
+		return c.level, c.ok
+	}
+
+	level, ok := lookupPrefixLevel(l.prefix)
+	l.prefixLevelCache.Store(&prefixLevelCache{gen: gen, level: level, ok: ok})
+	return level, ok
+}
@@ -0,0 +1,148 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/jopbrown/gobase/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerSetLevels(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := log.NewLoggerWithFormat(buf, log.LevelInfo, log.LevelFatal, log.SimpleLoggerFormat())
+	log.SetGlobalVerbose(1)
+	v1 := l.V(1).With("v1")
+
+	v1.Debug("hidden")
+	l.SetLevels(log.LevelDebug, log.LevelWarn)
+	v1.Debug("debug")
+	v1.Error("hidden")
+
+	minLevel, maxLevel := v1.(*log.Logger).Levels()
+	require.Equal(t, log.LevelDebug, minLevel)
+	require.Equal(t, log.LevelWarn, maxLevel)
+	require.Equal(t, "v1 debug\n", buf.String())
+}
+
+func TestLoggerSetLevelsShared(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	root := log.NewLoggerWithFormat(buf, log.LevelInfo, log.LevelFatal, log.SimpleLoggerFormat())
+	log.SetGlobalVerbose(0)
+	child := root.With("child").(*log.Logger)
+	sibling := root.WithFields("k", 1).(*log.Logger)
+
+	// the child changes the root and the sibling
+	child.SetLevels(log.LevelWarn, log.LevelFatal)
+	for _, l := range []*log.Logger{root, child, sibling} {
+		minLevel, maxLevel := l.Levels()
+		require.Equal(t, log.LevelWarn, minLevel)
+		require.Equal(t, log.LevelFatal, maxLevel)
+	}
+	root.Info("hidden")
+	sibling.Info("hidden")
+
+	// the root changes the children
+	root.SetLevels(log.LevelDebug, log.LevelInfo)
+	child.Debug("debug")
+	sibling.Info("info")
+	child.Warn("hidden")
+	for _, l := range []*log.Logger{root, child, sibling} {
+		minLevel, maxLevel := l.Levels()
+		require.Equal(t, log.LevelDebug, minLevel)
+		require.Equal(t, log.LevelInfo, maxLevel)
+	}
+	require.Equal(t, "child debug\ninfo k=1\n", buf.String())
+}
+
+func TestLoggerCloneLevels(t *testing.T) {
+	root := log.NewLoggerWithFormat(io.Discard, log.LevelInfo, log.LevelFatal, log.SimpleLoggerFormat())
+	clone := root.Clone()
+
+	clone.SetLevels(log.LevelError, log.LevelFatal)
+	minLevel, _ := root.Levels()
+	require.Equal(t, log.LevelInfo, minLevel)
+
+	root.SetLevels(log.LevelDebug, log.LevelFatal)
+	minLevel, _ = clone.Levels()
+	require.Equal(t, log.LevelError, minLevel)
+}
+
+func TestTeeLoggerSetLevels(t *testing.T) {
+	l1buf := bytes.NewBuffer(nil)
+	l2buf := bytes.NewBuffer(nil)
+	l1 := log.NewLoggerWithFormat(l1buf, log.LevelDebug, log.LevelInfo, log.SimpleLoggerFormat())
+	l2 := log.NewLoggerWithFormat(l2buf, log.LevelWarn, log.LevelFatal, log.SimpleLoggerFormat())
+	tee := log.NewTeeLogger(l1, l2)
+	log.SetGlobalVerbose(0)
+	db := tee.With("db")
+
+	tee.SetLevels(log.LevelInfo, log.LevelAll)
+	db.Debug("hidden")
+	db.Info("info")
+	db.Error("error")
+	db.S(false).Debug("hidden")
+	db.Print("print\n")
+
+	require.Equal(t, "db info\nprint\n", l1buf.String())
+	require.Equal(t, "db error\nprint\n", l2buf.String())
+}
+
+func TestSetPrefixLevel(t *testing.T) {
+	defer log.ResetPrefixLevels()
+
+	buf := bytes.NewBuffer(nil)
+	l := log.NewLoggerWithFormat(buf, log.LevelInfo, log.LevelFatal, log.SimpleLoggerFormat())
+	log.SetGlobalVerbose(0)
+	db := l.With("db")
+	sql := db.With("sql")
+	http := l.With("http")
+
+	sql.Debug("hidden")
+	log.SetPrefixLevel("db/sql", log.LevelDebug)
+	log.SetPrefixLevel("http", log.LevelError)
+	sql.Debug("debug")
+	db.Debug("hidden")
+	http.Warn("hidden")
+	http.Error("error")
+	l.Info("info")
+
+	log.SetPrefixLevel("db", log.LevelDebug)
+	db.Debug("debug")
+	log.RemovePrefixLevel("db/sql")
+	sql.Debug("debug again")
+
+	log.RemovePrefixLevel("db")
+	sql.Debug("hidden")
+
+	require.Equal(t, map[string]log.Level{"http": log.LevelError}, log.PrefixLevels())
+	require.Equal(t, "db/sql debug\nhttp error\ninfo\ndb debug\ndb/sql debug again\n", buf.String())
+}
+
+func TestSetPrefixLevelTee(t *testing.T) {
+	defer log.ResetPrefixLevels()
+
+	outBuf := bytes.NewBuffer(nil)
+	errBuf := bytes.NewBuffer(nil)
+	outLog := log.NewLoggerWithFormat(outBuf, log.LevelInfo, log.LevelInfo, log.SimpleLoggerFormat())
+	errLog := log.NewLoggerWithFormat(errBuf, log.LevelWarn, log.LevelFatal, log.SimpleLoggerFormat())
+	tee := log.NewTeeLogger(errLog, outLog)
+	log.SetGlobalVerbose(0)
+	db := tee.With("db")
+	http := tee.With("http")
+
+	log.SetPrefixLevel("db", log.LevelDebug)
+	log.SetPrefixLevel("http", log.LevelError)
+	db.Debug("dbg")
+	db.Info("info")
+	db.Warn("warn")
+	db.S(false).Debug("slog dbg")
+	tee.Debug("hidden")
+	http.Info("hidden")
+	http.Warn("hidden")
+	http.Error("error")
+
+	require.Equal(t, "db dbg\ndb info\ndb slog dbg\n", outBuf.String())
+	require.Equal(t, "db warn\nhttp error\n", errBuf.String())
+}
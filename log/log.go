@@ -2,11 +2,13 @@ package log
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,17 +25,20 @@ type Logger struct {
 	buf       []byte
 	isDiscard atomic.Bool
 
-	prefix   string
-	minLevel Level
-	maxLevel Level
-	verbose  int
+	prefix  string
+	fields  []slog.Attr
+	sampler *sampler
+	levels  *levelRange
+	verbose int
+
+	prefixLevelCache atomic.Pointer[prefixLevelCache]
+	vmoduleCache     atomic.Pointer[vmoduleMatch]
 }
 
 func NewLogger(out io.Writer, minLevel, maxLevel Level) *Logger {
 	l := &Logger{}
 	l.out = out
-	l.minLevel = minLevel
-	l.maxLevel = maxLevel
+	l.levels = newLevelRange(minLevel, maxLevel)
 	l.format = DefaultLoggerFormat()
 	if out == io.Discard {
 		l.isDiscard.Store(true)
@@ -75,26 +80,47 @@ func (l *Logger) SetOutput(w io.Writer) {
 	l.isDiscard.Store(w == io.Discard)
 }
 
+// Clone returns a copy of l with its own levels, SetLevels of the copy does not change l.
 func (l *Logger) Clone() *Logger {
-	newl := NewLogger(l.out, l.minLevel, l.maxLevel)
+	newl := l.clone()
+	newl.levels = newLevelRange(l.levels.get())
+	return newl
+}
+
+// clone returns a copy of l which shares the levels with l, it is used by V, With and WithFields.
+func (l *Logger) clone() *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	newl := NewLogger(l.out, LevelNone, LevelNone)
+	newl.levels = l.levels
 	newl.verbose = l.verbose
 	newl.format = l.format
 	newl.prefix = l.prefix
+	newl.fields = l.fields
+	newl.sampler = l.sampler
 	return newl
 }
 
 func (l *Logger) V(v int) ILogger {
-	newl := l.Clone()
+	newl := l.clone()
 	newl.verbose = l.verbose + v
 	return newl
 }
 
 func (l *Logger) With(prefix string) ILogger {
-	newl := l.Clone()
+	newl := l.clone()
 	newl.prefix = path.Join(l.prefix, prefix)
 	return newl
 }
 
+// WithFields returns a child logger which appends the attrs built from kv to every record,
+// kv is the same as the args of slog.Logger.Info.
+func (l *Logger) WithFields(kv ...any) ILogger {
+	newl := l.clone()
+	newl.fields = append(l.fields[:len(l.fields):len(l.fields)], argsToAttrs(kv)...)
+	return newl
+}
+
 func (l *Logger) S(json bool) *slog.Logger {
 	h := newSLoggerHandler(l, json)
 	s := slog.New(h)
@@ -103,33 +129,51 @@ func (l *Logger) S(json bool) *slog.Logger {
 }
 
 func (l *Logger) enabled(level Level) bool {
+	return l.allows(level, true)
+}
+
+// allows reports whether l writes the records of level,
+// the prefix level replaces the min level of l if widen, otherwise it can only raise it.
+// TeeLogger decides which loggers are widened, so a record is not duplicated by the loggers of level ranges.
+func (l *Logger) allows(level Level, widen bool) bool {
 	if l.isDiscard.Load() {
 		return false
 	}
 
-	if l.verbose > int(globalVerbose.Load()) {
+	if level == LevelNone {
 		return false
 	}
 
-	if level == LevelAll {
-		return true
-	}
+	if level != LevelAll {
+		minLevel, maxLevel := l.levels.get()
+		if prefixLevel, ok := l.prefixLevel(); ok && (widen || prefixLevel > minLevel) {
+			minLevel = prefixLevel
+		}
 
-	if level == LevelNone {
-		return false
-	}
+		if level < minLevel {
+			return false
+		}
 
-	if level < l.minLevel {
-		return false
+		if level > maxLevel {
+			return false
+		}
 	}
 
-	if level > l.maxLevel {
+	// the threshold is the global verbose or a rule of vmodule which is never negative,
+	// so the lookup is skipped if neither can be below l.verbose
+	if (l.verbose > 0 || l.verbose > int(globalVerbose.Load())) && l.verbose > l.verboseThreshold() {
 		return false
 	}
 
 	return true
 }
 
+// lowestLevel returns the min level of l without the prefix level.
+func (l *Logger) lowestLevel() Level {
+	minLevel, _ := l.levels.get()
+	return minLevel
+}
+
 func itoa(buf *[]byte, i int, wid int) {
 	var b [20]byte
 	bp := len(b) - 1
@@ -144,24 +188,79 @@ func itoa(buf *[]byte, i int, wid int) {
 	*buf = append(*buf, b[bp:]...)
 }
 
-func getCaller(skip int) *runtime.Frame {
+func getCallerPC(skip int) uintptr {
 	callers := make([]uintptr, 1)
 	n := runtime.Callers(skip, callers[:])
 	if n < 1 {
+		return 0
+	}
+	return callers[0]
+}
+
+func getFrame(pc uintptr) *runtime.Frame {
+	if pc == 0 {
 		return nil
 	}
-	frame, _ := runtime.CallersFrames(callers).Next()
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
 	return &frame
 }
 
-func (l *Logger) output(calldepth int, level Level, msg string) error {
+func (l *Logger) output(calldepth int, level Level, template, msg string, attrs []slog.Attr) error {
 	now := time.Now()
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	needFrame := l.format.AddSource || l.format.AddCaller
+	var pc uintptr
+	if needFrame || l.sampler != nil {
+		pc = getCallerPC(calldepth + 1)
+	}
+
+	if l.sampler != nil {
+		if !l.sampler.allow(l, now, level, pc, template) {
+			return nil
+		}
+	}
+
+	var frame *runtime.Frame
+	if needFrame {
+		frame = getFrame(pc)
+	}
+
+	if len(l.fields) > 0 {
+		attrs = append(l.fields[:len(l.fields):len(l.fields)], attrs...)
+	}
+
+	return l.writeRecord(now, level, frame, msg, attrs)
+}
+
+// writeRecord formats a record by l.format and writes it to l.out, l.mu must be held.
+func (l *Logger) writeRecord(now time.Time, level Level, frame *runtime.Frame, msg string, attrs []slog.Attr) error {
 	l.buf = l.buf[:0]
 
+	switch l.format.Encoding {
+	case EncodingJSON:
+		l.appendJSONRecord(now, level, frame, msg, attrs)
+	case EncodingLogfmt:
+		l.appendLogfmtRecord(now, level, frame, msg, attrs)
+	default:
+		l.appendTextRecord(now, level, frame, msg, attrs)
+	}
+
+	var err error
+	if lw, ok := l.out.(LevelWriter); ok {
+		_, err = lw.WriteLevel(level, l.buf)
+	} else {
+		_, err = l.out.Write(l.buf)
+	}
+	if err != nil {
+		return errors.ErrorAt(err)
+	}
+	return nil
+}
+
+func (l *Logger) appendTextRecord(now time.Time, level Level, frame *runtime.Frame, msg string, attrs []slog.Attr) {
 	if l.format.AddLevel {
 		lvlStr := level.String()
 		l.buf = append(l.buf, lvlStr...)
@@ -199,24 +298,9 @@ func (l *Logger) output(calldepth int, level Level, msg string) error {
 		}
 	}
 
-	if l.format.AddSource || l.format.AddCaller {
-		frame := getCaller(calldepth + 1)
-
+	if frame != nil {
 		if l.format.AddSource {
-			file := frame.File
-			fileDepthCount := 0
-			for i := len(file) - 1; i > 0; i-- {
-				if file[i] == '/' {
-					newFile := file[i+1:]
-					fileDepthCount++
-					if l.format.SourceDepth > 0 && fileDepthCount >= l.format.SourceDepth {
-						file = newFile
-						break
-					}
-				}
-			}
-
-			l.buf = append(l.buf, file...)
+			l.buf = append(l.buf, trimSourceFile(frame.File, l.format.SourceDepth)...)
 			l.buf = append(l.buf, ':')
 			itoa(&l.buf, frame.Line, -1)
 			l.buf = append(l.buf, ": "...)
@@ -237,15 +321,31 @@ func (l *Logger) output(calldepth int, level Level, msg string) error {
 		l.buf = append(l.buf, ' ')
 	}
 
+	if len(attrs) > 0 {
+		msg = strings.TrimSuffix(msg, "\n")
+	}
 	l.buf = append(l.buf, msg...)
-	if len(msg) == 0 || msg[len(msg)-1] != '\n' {
+	for _, a := range attrs {
+		l.buf = appendTextAttr(l.buf, "", a)
+	}
+	if len(l.buf) == 0 || l.buf[len(l.buf)-1] != '\n' {
 		l.buf = append(l.buf, '\n')
 	}
-	_, err := l.out.Write(l.buf)
-	if err != nil {
-		return errors.ErrorAt(err)
+}
+
+func trimSourceFile(file string, depth int) string {
+	fileDepthCount := 0
+	for i := len(file) - 1; i > 0; i-- {
+		if file[i] == '/' {
+			newFile := file[i+1:]
+			fileDepthCount++
+			if depth > 0 && fileDepthCount >= depth {
+				file = newFile
+				break
+			}
+		}
 	}
-	return nil
+	return file
 }
 
 func (l *Logger) Print(a ...any) {
@@ -282,7 +382,7 @@ func (l *Logger) Debug(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	l.output(3, LevelDebug, msg)
+	l.output(3, LevelDebug, "", msg, nil)
 }
 
 func (l *Logger) Debugf(format string, a ...any) {
@@ -290,7 +390,7 @@ func (l *Logger) Debugf(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	l.output(3, LevelDebug, msg)
+	l.output(3, LevelDebug, format, msg, nil)
 }
 
 func (l *Logger) Info(a ...any) {
@@ -298,7 +398,7 @@ func (l *Logger) Info(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	l.output(3, LevelInfo, msg)
+	l.output(3, LevelInfo, "", msg, nil)
 }
 
 func (l *Logger) Infof(format string, a ...any) {
@@ -306,7 +406,7 @@ func (l *Logger) Infof(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	l.output(3, LevelInfo, msg)
+	l.output(3, LevelInfo, format, msg, nil)
 }
 
 func (l *Logger) Warn(a ...any) {
@@ -314,7 +414,7 @@ func (l *Logger) Warn(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	l.output(3, LevelWarn, msg)
+	l.output(3, LevelWarn, "", msg, nil)
 }
 
 func (l *Logger) Warnf(format string, a ...any) {
@@ -322,7 +422,7 @@ func (l *Logger) Warnf(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	l.output(3, LevelWarn, msg)
+	l.output(3, LevelWarn, format, msg, nil)
 }
 
 func (l *Logger) Error(a ...any) {
@@ -330,7 +430,7 @@ func (l *Logger) Error(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	l.output(3, LevelError, msg)
+	l.output(3, LevelError, "", msg, nil)
 }
 
 func (l *Logger) Errorf(format string, a ...any) {
@@ -338,7 +438,63 @@ func (l *Logger) Errorf(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	l.output(3, LevelError, msg)
+	l.output(3, LevelError, format, msg, nil)
+}
+
+func (l *Logger) Debugw(msg string, kv ...any) {
+	if !l.enabled(LevelDebug) {
+		return
+	}
+	l.output(3, LevelDebug, msg, msg, argsToAttrs(kv))
+}
+
+func (l *Logger) Infow(msg string, kv ...any) {
+	if !l.enabled(LevelInfo) {
+		return
+	}
+	l.output(3, LevelInfo, msg, msg, argsToAttrs(kv))
+}
+
+func (l *Logger) Warnw(msg string, kv ...any) {
+	if !l.enabled(LevelWarn) {
+		return
+	}
+	l.output(3, LevelWarn, msg, msg, argsToAttrs(kv))
+}
+
+func (l *Logger) Errorw(msg string, kv ...any) {
+	if !l.enabled(LevelError) {
+		return
+	}
+	l.output(3, LevelError, msg, msg, argsToAttrs(kv))
+}
+
+func (l *Logger) DebugContext(ctx context.Context, msg string, kv ...any) {
+	if !l.enabled(LevelDebug) {
+		return
+	}
+	l.output(3, LevelDebug, msg, msg, contextArgsToAttrs(ctx, kv))
+}
+
+func (l *Logger) InfoContext(ctx context.Context, msg string, kv ...any) {
+	if !l.enabled(LevelInfo) {
+		return
+	}
+	l.output(3, LevelInfo, msg, msg, contextArgsToAttrs(ctx, kv))
+}
+
+func (l *Logger) WarnContext(ctx context.Context, msg string, kv ...any) {
+	if !l.enabled(LevelWarn) {
+		return
+	}
+	l.output(3, LevelWarn, msg, msg, contextArgsToAttrs(ctx, kv))
+}
+
+func (l *Logger) ErrorContext(ctx context.Context, msg string, kv ...any) {
+	if !l.enabled(LevelError) {
+		return
+	}
+	l.output(3, LevelError, msg, msg, contextArgsToAttrs(ctx, kv))
 }
 
 func (l *Logger) ErrorAt(err error, a ...any) error {
@@ -350,7 +506,7 @@ func (l *Logger) ErrorAt(err error, a ...any) error {
 	if !l.enabled(LevelError) {
 		return err
 	}
-	l.output(3, LevelError, errors.GetErrorDetails(err))
+	l.output(3, LevelError, "", errors.GetErrorDetails(err), nil)
 	return err
 }
 
@@ -363,14 +519,14 @@ func (l *Logger) ErrorAtf(err error, format string, a ...any) error {
 	if !l.enabled(LevelError) {
 		return err
 	}
-	l.output(3, LevelError, errors.GetErrorDetails(err))
+	l.output(3, LevelError, format, errors.GetErrorDetails(err), nil)
 	return err
 }
 
 func (l *Logger) Fatal(a ...any) {
 	if l.enabled(LevelFatal) {
 		msg := fmt.Sprint(a...)
-		l.output(3, LevelFatal, msg)
+		l.output(3, LevelFatal, "", msg, nil)
 	}
 	os.Exit(1)
 }
@@ -378,7 +534,7 @@ func (l *Logger) Fatal(a ...any) {
 func (l *Logger) Fatalf(format string, a ...any) {
 	if l.enabled(LevelFatal) {
 		msg := fmt.Sprintf(format, a...)
-		l.output(3, LevelFatal, msg)
+		l.output(3, LevelFatal, format, msg, nil)
 	}
 	os.Exit(1)
 }
@@ -386,7 +542,7 @@ func (l *Logger) Fatalf(format string, a ...any) {
 func (l *Logger) Panic(a ...any) {
 	msg := fmt.Sprint(a...)
 	if l.enabled(LevelPanic) {
-		l.output(3, LevelPanic, msg)
+		l.output(3, LevelPanic, "", msg, nil)
 	}
 	panic(msg)
 }
@@ -394,7 +550,7 @@ func (l *Logger) Panic(a ...any) {
 func (l *Logger) Panicf(format string, a ...any) {
 	msg := fmt.Sprintf(format, a...)
 	if l.enabled(LevelPanic) {
-		l.output(3, LevelPanic, msg)
+		l.output(3, LevelPanic, format, msg, nil)
 	}
 	panic(msg)
 }
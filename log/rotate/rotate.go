@@ -63,6 +63,14 @@ func getCreateTime(fd *os.File) time.Time {
 }
 
 func (w *Writer) Write(p []byte) (n int, err error) {
+	if w.fd == nil {
+		// the last rotation failed to reopen the file
+		err = w.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	n, err = w.fd.Write(p)
 	if err != nil {
 		return
@@ -74,8 +82,18 @@ func (w *Writer) Write(p []byte) (n int, err error) {
 	return
 }
 
-func (w *Writer) Close() error {
-	return w.fd.Close()
+func (w *Writer) Close() (err error) {
+	if w.fd == nil {
+		return nil
+	}
+	defer errors.CaptureClose(&err, w.fd, "unable to close log file: ", w.fpath)
+
+	err = w.fd.Sync()
+	if err != nil {
+		return errors.ErrorAt(err, "unable to flush log file: ", w.fpath)
+	}
+
+	return nil
 }
 
 func (w *Writer) doRotate() error {
@@ -103,28 +121,37 @@ func (w *Writer) doRotate() error {
 	return nil
 }
 
-func (w *Writer) rotateFile(now time.Time) error {
+func (w *Writer) rotateFile(now time.Time) (err error) {
 	w.rotateCount++
 	noext, ext := filePathSplitByExt(w.fpath)
 	// backupPath := noext +  + ext
 	backupPath := fmt.Sprintf("%s.%s_%02d%s", noext, now.Format("20060102_150405"), w.rotateCount, ext)
 
-	err := w.fd.Close()
-	if err != nil {
-		errors.ErrorAt(err)
+	if cerr := w.fd.Close(); cerr != nil {
+		err = errors.ErrorAt(cerr, "unable to close log file: ", w.fpath)
 	}
+	w.fd = nil
+	w.lastRotateTime = now
 
-	err = os.Rename(w.fpath, backupPath)
-	if err != nil {
-		errors.ErrorAt(err)
+	// the current file is reopened and written continually if it can not be renamed
+	if rerr := os.Rename(w.fpath, backupPath); rerr != nil {
+		err = errors.Join(err, errors.ErrorAtf(rerr, "unable to rename %s to %s", w.fpath, backupPath))
 	}
 
-	w.fd, err = os.OpenFile(w.fpath, os.O_CREATE|os.O_WRONLY, w.perm)
-	if err != nil {
-		errors.ErrorAt(err)
+	if oerr := w.open(); oerr != nil {
+		return errors.Join(err, oerr)
 	}
-	w.lastRotateTime = now
 
+	return err
+}
+
+// open opens w.fpath for appending, w.fd is left nil if it fails so the next Write retries.
+func (w *Writer) open() error {
+	fd, err := os.OpenFile(w.fpath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, w.perm)
+	if err != nil {
+		return errors.ErrorAt(err, "unable to reopen log file: ", w.fpath)
+	}
+	w.fd = fd
 	return nil
 }
 
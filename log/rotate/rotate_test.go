@@ -14,9 +14,9 @@ import (
 )
 
 func TestOpenFile(t *testing.T) {
-	os.RemoveAll("tmp")
+	dir := t.TempDir()
 
-	w, err := rotate.OpenFile("tmp/test.log", 300*time.Millisecond, 0)
+	w, err := rotate.OpenFile(filepath.Join(dir, "test.log"), 300*time.Millisecond, 0)
 	require.NoError(t, err)
 
 	fmt.Fprintf(w, "aaa")
@@ -32,9 +32,41 @@ func TestOpenFile(t *testing.T) {
 	fmt.Fprintf(w, "eee")
 	fmt.Fprintf(w, "fff")
 
-	assert.Equal(t, "aaabbbccc", string(errors.Must1(os.ReadFile(errors.Must1(filepath.Glob("tmp/*_01.log"))[0]))))
-	assert.Equal(t, "dddeee", string(errors.Must1(os.ReadFile(errors.Must1(filepath.Glob("tmp/*_02.log"))[0]))))
-	assert.Equal(t, "fff", string(errors.Must1(os.ReadFile("tmp/test.log"))))
+	assert.Equal(t, "aaabbbccc", string(errors.Must1(os.ReadFile(errors.Must1(filepath.Glob(filepath.Join(dir, "*_01.log")))[0]))))
+	assert.Equal(t, "dddeee", string(errors.Must1(os.ReadFile(errors.Must1(filepath.Glob(filepath.Join(dir, "*_02.log")))[0]))))
+	assert.Equal(t, "fff", string(errors.Must1(os.ReadFile(filepath.Join(dir, "test.log")))))
 
 	w.Close()
 }
+
+func TestRotateFailure(t *testing.T) {
+	logDir := filepath.Join(t.TempDir(), "logs")
+	logPath := filepath.Join(logDir, "test.log")
+	require.NoError(t, os.Mkdir(logDir, 0o755))
+
+	w, err := rotate.OpenFile(logPath, 50*time.Millisecond, 0)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("aaa"))
+	require.NoError(t, err)
+
+	// the rename fails, the file is reopened
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, os.Remove(logPath))
+	_, err = w.Write([]byte("bbb"))
+	require.Error(t, err)
+	_, err = w.Write([]byte("ccc"))
+	require.NoError(t, err)
+	assert.Equal(t, "ccc", string(errors.Must1(os.ReadFile(logPath))))
+
+	// the reopen fails, the next write retries it
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, os.RemoveAll(logDir))
+	_, err = w.Write([]byte("ddd"))
+	require.Error(t, err)
+	require.NoError(t, os.Mkdir(logDir, 0o755))
+	_, err = w.Write([]byte("eee"))
+	require.NoError(t, err)
+	assert.Equal(t, "eee", string(errors.Must1(os.ReadFile(logPath))))
+
+	require.NoError(t, w.Close())
+}
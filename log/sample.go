@@ -0,0 +1,208 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"log/slog"
+)
+
+// SamplingOptions limits the records written by a Logger,
+// the records are keyed by the level, the call site and the message template.
+// The template is the format of the printf-style methods and the message of the structured ones,
+// the records of the others like Info are keyed by the level and the call site only.
+type SamplingOptions struct {
+	// Interval is the window of the counters, default is 1 second.
+	Interval time.Duration
+	// First is the number of the records of a key written in each interval.
+	First int
+	// Thereafter writes every Thereafter-th record of a key after First, 0 suppresses them all.
+	Thereafter int
+	// LevelLimits is the max number of the records of each level written in each interval,
+	// the level without limit is unlimited.
+	LevelLimits map[Level]int
+}
+
+type sampleKey struct {
+	level    Level
+	pc       uintptr
+	template string
+}
+
+type sampleCounter struct {
+	start      time.Time
+	n          int
+	suppressed uint64
+	// l is the last logger suppressed the record of the key, the summary is written by it.
+	l *Logger
+}
+
+type levelCounter struct {
+	start time.Time
+	n     int
+}
+
+// sampler is shared by the logger and the clones made by V, With and WithFields.
+type sampler struct {
+	opts SamplingOptions
+
+	mu       sync.Mutex
+	counters map[sampleKey]*sampleCounter
+	levels   map[Level]*levelCounter
+	pending  bool
+	pruned   time.Time
+}
+
+func newSampler(opts SamplingOptions) *sampler {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	s := &sampler{}
+	s.opts = opts
+	s.counters = make(map[sampleKey]*sampleCounter)
+	s.levels = make(map[Level]*levelCounter)
+	return s
+}
+
+// SetSampling enables the sampling of l and the clones made from l afterwards.
+func (l *Logger) SetSampling(opts SamplingOptions) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sampler = newSampler(opts)
+}
+
+// DisableSampling disables the sampling of l.
+func (l *Logger) DisableSampling() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sampler = nil
+}
+
+// SetSampling enables the sampling of each logger of tee, the loggers count the records separately.
+func (tee *TeeLogger) SetSampling(opts SamplingOptions) {
+	for _, l := range tee.loggers {
+		if sl, ok := l.(interface{ SetSampling(opts SamplingOptions) }); ok {
+			sl.SetSampling(opts)
+		}
+	}
+}
+
+func (s *sampler) allow(l *Logger, now time.Time, level Level, pc uintptr, template string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.pruned) >= s.opts.Interval {
+		s.prune(now)
+	}
+
+	key := sampleKey{level: level, pc: pc, template: template}
+	c := s.counters[key]
+	if c == nil {
+		c = &sampleCounter{start: now}
+		s.counters[key] = c
+	}
+	if now.Sub(c.start) >= s.opts.Interval {
+		c.start = now
+		c.n = 0
+	}
+	c.n++
+
+	ok := c.n <= s.opts.First || (s.opts.Thereafter > 0 && (c.n-s.opts.First)%s.opts.Thereafter == 0)
+	if ok {
+		ok = s.allowLevel(now, level)
+	}
+
+	if !ok {
+		c.suppressed++
+		c.l = l
+		if !s.pending {
+			s.pending = true
+			time.AfterFunc(s.opts.Interval, s.summarize)
+		}
+	}
+
+	return ok
+}
+
+// prune removes the expired counters which have no suppressed record to summarize,
+// it is called at most once per interval so the counters of the keys not seen again are not kept.
+func (s *sampler) prune(now time.Time) {
+	s.pruned = now
+	for key, c := range s.counters {
+		if c.suppressed == 0 && now.Sub(c.start) >= s.opts.Interval {
+			delete(s.counters, key)
+		}
+	}
+}
+
+func (s *sampler) allowLevel(now time.Time, level Level) bool {
+	limit := s.opts.LevelLimits[level]
+	if limit <= 0 {
+		return true
+	}
+
+	lc := s.levels[level]
+	if lc == nil {
+		lc = &levelCounter{start: now}
+		s.levels[level] = lc
+	}
+	if now.Sub(lc.start) >= s.opts.Interval {
+		lc.start = now
+		lc.n = 0
+	}
+	if lc.n >= limit {
+		return false
+	}
+	lc.n++
+	return true
+}
+
+type sampleSummary struct {
+	key        sampleKey
+	suppressed uint64
+	l          *Logger
+}
+
+// summarize writes a summary for each key which has suppressed records,
+// and removes the expired counters.
+func (s *sampler) summarize() {
+	now := time.Now()
+
+	s.mu.Lock()
+	summaries := make([]sampleSummary, 0, 1)
+	for key, c := range s.counters {
+		if c.suppressed > 0 {
+			summaries = append(summaries, sampleSummary{key: key, suppressed: c.suppressed, l: c.l})
+			c.suppressed = 0
+			c.l = nil
+		}
+		if now.Sub(c.start) >= s.opts.Interval {
+			delete(s.counters, key)
+		}
+	}
+	s.pending = false
+	s.mu.Unlock()
+
+	for _, sum := range summaries {
+		sum.l.writeSummary(now, sum)
+	}
+}
+
+func (l *Logger) writeSummary(now time.Time, sum sampleSummary) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var frame *runtime.Frame
+	if l.format.AddSource || l.format.AddCaller {
+		frame = getFrame(sum.key.pc)
+	}
+
+	msg := fmt.Sprintf("suppressed %d similar messages", sum.suppressed)
+	var attrs []slog.Attr
+	if sum.key.template != "" {
+		attrs = append(attrs, slog.String("template", sum.key.template))
+	}
+	l.writeRecord(now, sum.key.level, frame, msg, attrs)
+}
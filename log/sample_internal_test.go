@@ -0,0 +1,32 @@
+package log
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplerPrune(t *testing.T) {
+	l := NewLoggerWithFormat(io.Discard, LevelDebug, LevelFatal, SimpleLoggerFormat())
+	s := newSampler(SamplingOptions{Interval: time.Second, First: 1})
+
+	now := time.Unix(0, 0)
+	for i := 0; i < 1000; i++ {
+		require.True(t, s.allow(l, now, LevelInfo, uintptr(i), "unique"))
+	}
+	require.Len(t, s.counters, 1000)
+
+	// no record is suppressed, the expired counters are still removed
+	now = now.Add(time.Second)
+	require.True(t, s.allow(l, now, LevelInfo, 0, "unique"))
+	require.Len(t, s.counters, 1)
+	require.False(t, s.pending)
+
+	// the counter with suppressed records is kept for the summary
+	require.False(t, s.allow(l, now, LevelInfo, 0, "unique"))
+	now = now.Add(2 * time.Second)
+	require.True(t, s.allow(l, now, LevelInfo, 1, "unique"))
+	require.Len(t, s.counters, 2)
+}
@@ -0,0 +1,110 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jopbrown/gobase/log"
+	"github.com/stretchr/testify/require"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Split(strings.TrimSpace(b.buf.String()), "\n")
+}
+
+func TestLoggerSampling(t *testing.T) {
+	buf := &syncBuffer{}
+	l := log.NewLoggerWithFormat(buf, log.LevelDebug, log.LevelFatal, log.SimpleLoggerFormat())
+	l.SetSampling(log.SamplingOptions{Interval: time.Hour, First: 2, Thereafter: 3})
+	log.SetGlobalVerbose(1)
+
+	v1 := l.V(1).With("v1")
+	for i := 1; i <= 10; i++ {
+		v1.Debugf("loop %d", i)
+		l.Infof("other %d", i)
+	}
+	l.Info("once")
+	l.Info("once")
+
+	require.Equal(t, []string{
+		"v1 loop 1", "other 1",
+		"v1 loop 2", "other 2",
+		"v1 loop 5", "other 5",
+		"v1 loop 8", "other 8",
+		"once", "once",
+	}, buf.Lines())
+}
+
+func TestLoggerSamplingCallSite(t *testing.T) {
+	buf := &syncBuffer{}
+	l := log.NewLoggerWithFormat(buf, log.LevelDebug, log.LevelFatal, log.SimpleLoggerFormat())
+	l.SetSampling(log.SamplingOptions{Interval: time.Hour, First: 2})
+	log.SetGlobalVerbose(0)
+
+	for i := 0; i < 10; i++ {
+		l.Debug("item ", i)
+		l.Infow("item", "i", i)
+	}
+
+	require.Equal(t, []string{"item 0", "item i=0", "item 1", "item i=1"}, buf.Lines())
+}
+
+func TestLoggerSamplingSummary(t *testing.T) {
+	buf := &syncBuffer{}
+	format := log.TestLoggerFormat()
+	format.AddVerbose = false
+	l := log.NewLoggerWithFormat(buf, log.LevelDebug, log.LevelFatal, format)
+	l.SetSampling(log.SamplingOptions{Interval: 50 * time.Millisecond, First: 1})
+	log.SetGlobalVerbose(0)
+
+	for i := 0; i < 100; i++ {
+		l.Warnf("hot %d", i)
+	}
+
+	require.Eventually(t, func() bool { return len(buf.Lines()) == 2 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{
+		"WARN  log_test.TestLoggerSamplingSummary hot 0",
+		`WARN  log_test.TestLoggerSamplingSummary suppressed 99 similar messages template="hot %d"`,
+	}, buf.Lines())
+
+	time.Sleep(60 * time.Millisecond)
+	l.Warnf("hot %d", 100)
+	require.Len(t, buf.Lines(), 3)
+}
+
+func TestLoggerLevelLimit(t *testing.T) {
+	buf := &syncBuffer{}
+	l := log.NewLoggerWithFormat(buf, log.LevelDebug, log.LevelFatal, log.SimpleLoggerFormat())
+	l.SetSampling(log.SamplingOptions{
+		Interval:    time.Hour,
+		First:       100,
+		LevelLimits: map[log.Level]int{log.LevelDebug: 3},
+	})
+	log.SetGlobalVerbose(0)
+
+	s := l.S(false)
+	for i := 0; i < 5; i++ {
+		l.Debugf("a %d", i)
+		s.Debug("b", "i", i)
+		l.Infof("c %d", i)
+	}
+
+	lines := buf.Lines()
+	require.Equal(t, []string{"a 0", "b i=0", "c 0", "a 1", "c 1", "c 2", "c 3", "c 4"}, lines)
+}
@@ -2,21 +2,136 @@ package log
 
 import (
 	"context"
+	"runtime"
+	"time"
 
 	"log/slog"
 
 	"github.com/jopbrown/gobase/errors"
 )
 
+// sLoggerHandler renders slog records by the LoggerFormat of l,
+// the attrs are appended after the message as key=value.
 type sLoggerHandler struct {
+	l      *Logger
+	groups []attrGroup
+}
+
+// attrGroup is a group opened by WithGroup and the attrs added to it by WithAttrs,
+// the first one is the unnamed root group.
+type attrGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+func newSLoggerHandler(l *Logger, json bool) slog.Handler {
+	if json {
+		return newSJSONHandler(l)
+	}
+
+	h := &sLoggerHandler{}
+	h.l = l
+	h.groups = []attrGroup{{attrs: l.fields}}
+	return h
+}
+
+func (h *sLoggerHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.l.enabled(Level(level))
+}
+
+func (h *sLoggerHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := h.collectAttrs(contextAttrs(ctx), r)
+
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	h.l.mu.Lock()
+	defer h.l.mu.Unlock()
+
+	if h.l.sampler != nil && !h.l.sampler.allow(h.l, now, Level(r.Level), r.PC, r.Message) {
+		return nil
+	}
+
+	var frame *runtime.Frame
+	if h.l.format.AddSource || h.l.format.AddCaller {
+		frame = getFrame(r.PC)
+	}
+
+	return h.l.writeRecord(now, Level(r.Level), frame, r.Message, attrs)
+}
+
+// collectAttrs nests the attrs of r into the opened groups,
+// the groups without any attr are omitted.
+// The ctxAttrs are placed after the attrs of the root group as Logger.output does.
+func (h *sLoggerHandler) collectAttrs(ctxAttrs []slog.Attr, r slog.Record) []slog.Attr {
+	last := len(h.groups) - 1
+	attrs := make([]slog.Attr, 0, len(h.groups[last].attrs)+len(ctxAttrs)+r.NumAttrs())
+	attrs = append(attrs, h.groups[last].attrs...)
+	if last == 0 {
+		attrs = append(attrs, ctxAttrs...)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	for i := last; i > 0; i-- {
+		parent := h.groups[i-1].attrs
+		grouped := make([]slog.Attr, 0, len(parent)+len(ctxAttrs)+1)
+		grouped = append(grouped, parent...)
+		if i == 1 {
+			grouped = append(grouped, ctxAttrs...)
+		}
+		if len(attrs) > 0 {
+			grouped = append(grouped, slog.Attr{Key: h.groups[i].name, Value: slog.GroupValue(attrs...)})
+		}
+		attrs = grouped
+	}
+
+	return attrs
+}
+
+func (h *sLoggerHandler) clone() *sLoggerHandler {
+	newH := &sLoggerHandler{}
+	newH.l = h.l
+	newH.groups = make([]attrGroup, len(h.groups), len(h.groups)+1)
+	copy(newH.groups, h.groups)
+	return newH
+}
+
+func (h *sLoggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	newH := h.clone()
+	last := &newH.groups[len(newH.groups)-1]
+	last.attrs = append(last.attrs[:len(last.attrs):len(last.attrs)], attrs...)
+	return newH
+}
+
+func (h *sLoggerHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	newH := h.clone()
+	newH.groups = append(newH.groups, attrGroup{name: name})
+	return newH
+}
+
+// sJSONHandler writes the records of slog.JSONHandler to l.out under l.mu,
+// the records are sampled as the ones of sLoggerHandler.
+type sJSONHandler struct {
 	l *Logger
+	w *recordWriter
 	slog.Handler
 }
 
-func newSLoggerHandler(l *Logger, json bool) *sLoggerHandler {
+func newSJSONHandler(l *Logger) *sJSONHandler {
 	opts := &slog.HandlerOptions{
 		AddSource: l.format.AddSource,
-		Level:     l.minLevel,
+		Level:     LevelNone,
 	}
 	opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
 		if a.Key == slog.TimeKey {
@@ -26,12 +141,8 @@ func newSLoggerHandler(l *Logger, json bool) *sLoggerHandler {
 		}
 		return a
 	}
-	var h slog.Handler
-	if json {
-		h = slog.NewJSONHandler(l.out, opts)
-	} else {
-		h = slog.NewTextHandler(l.out, opts)
-	}
+	w := &recordWriter{l: l}
+	var h slog.Handler = slog.NewJSONHandler(w, opts)
 
 	attrs := make([]slog.Attr, 0, 2)
 	if l.verbose > 0 {
@@ -41,40 +152,81 @@ func newSLoggerHandler(l *Logger, json bool) *sLoggerHandler {
 	if l.prefix != "" {
 		attrs = append(attrs, slog.String("prefix", l.prefix))
 	}
+	attrs = append(attrs, l.fields...)
 	if len(attrs) > 0 {
 		h = h.WithAttrs(attrs)
 	}
 
-	sh := &sLoggerHandler{}
+	sh := &sJSONHandler{}
 	sh.l = l
+	sh.w = w
 	sh.Handler = h
 
 	return sh
 }
 
-func (h *sLoggerHandler) Enabled(ctx context.Context, level slog.Level) bool {
+func (h *sJSONHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return h.l.enabled(Level(level))
 }
 
-func (h *sLoggerHandler) clone() *sLoggerHandler {
-	newH := &sLoggerHandler{}
+// Handle adds the attrs carried by ctx to r, they are in the opened groups of h.
+func (h *sJSONHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctxAttrs := contextAttrs(ctx); len(ctxAttrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(ctxAttrs...)
+	}
+
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	h.l.mu.Lock()
+	defer h.l.mu.Unlock()
+
+	if h.l.sampler != nil && !h.l.sampler.allow(h.l, now, Level(r.Level), r.PC, r.Message) {
+		return nil
+	}
+
+	// the handler of slog writes the record to recordWriter before Handle returns
+	h.w.level = Level(r.Level)
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *sJSONHandler) clone() *sJSONHandler {
+	newH := &sJSONHandler{}
 	newH.l = h.l
+	newH.w = h.w
 	newH.Handler = h.Handler
 	return newH
 }
 
-func (h *sLoggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+func (h *sJSONHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	newH := h.clone()
 	newH.Handler = h.Handler.WithAttrs(attrs)
 	return newH
 }
 
-func (h *sLoggerHandler) WithGroup(name string) slog.Handler {
+func (h *sJSONHandler) WithGroup(name string) slog.Handler {
 	newH := h.clone()
 	newH.Handler = h.Handler.WithGroup(name)
 	return newH
 }
 
+// recordWriter writes the record being handled by sJSONHandler to the current output of l,
+// l.mu is held by sJSONHandler.Handle.
+type recordWriter struct {
+	l     *Logger
+	level Level
+}
+
+func (w *recordWriter) Write(p []byte) (int, error) {
+	if lw, ok := w.l.out.(LevelWriter); ok {
+		return lw.WriteLevel(w.level, p)
+	}
+	return w.l.out.Write(p)
+}
+
 type sTeeLoggerHandler struct {
 	tee *TeeLogger
 	hs  []slog.Handler
@@ -104,12 +256,9 @@ func (th *sTeeLoggerHandler) Enabled(ctx context.Context, level slog.Level) bool
 
 func (th *sTeeLoggerHandler) Handle(ctx context.Context, r slog.Record) error {
 	var err error
-	for _, h := range th.hs {
-		if !h.Enabled(ctx, r.Level) {
-			continue
-		}
-
-		err = errors.Join(err, h.Handle(ctx, r))
+	var buf [4]int
+	for _, i := range th.tee.route(Level(r.Level), true, buf[:0]) {
+		err = errors.Join(err, th.hs[i].Handle(ctx, r))
 	}
 
 	return err
@@ -0,0 +1,135 @@
+package log_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"log/slog"
+
+	"github.com/jopbrown/gobase/log"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleLogger_S() {
+	l := log.NewLoggerWithFormat(os.Stdout, log.LevelDebug, log.LevelFatal, log.TestLoggerFormat())
+	log.SetGlobalVerbose(1)
+
+	l.Info("Info")
+	s := l.S(false)
+	s.Info("Info", slog.String("attrString", "Info String"), slog.Int("attrInt", 1))
+
+	v1 := l.V(1).With("V1")
+	v1.Warn("Warn")
+	v1.S(false).Warn("Warn", "key", "value")
+
+	// Output:
+	// INFO  V0 log_test.ExampleLogger_S Info
+	// INFO  V0 log_test.ExampleLogger_S Info attrString="Info String" attrInt=1
+	// WARN  V1 log_test.ExampleLogger_S V1 Warn
+	// WARN  V1 log_test.ExampleLogger_S V1 Warn key=value
+}
+
+func ExampleLogger_S_group() {
+	l := log.NewLoggerWithFormat(os.Stdout, log.LevelDebug, log.LevelFatal, log.TestLoggerFormat())
+	log.SetGlobalVerbose(0)
+
+	s := l.S(false).With("id", 1).WithGroup("GROUP").With(slog.String("with", "something"))
+	s.Info("Info", slog.String("attrString", "InfoString"))
+	s.WithGroup("EMPTY").Info("Info")
+	s.Info("Info", slog.Group("sub", slog.Bool("ok", true)))
+
+	// Output:
+	// INFO  V0 log_test.ExampleLogger_S_group Info id=1 GROUP.with=something GROUP.attrString=InfoString
+	// INFO  V0 log_test.ExampleLogger_S_group Info id=1 GROUP.with=something
+	// INFO  V0 log_test.ExampleLogger_S_group Info id=1 GROUP.with=something GROUP.sub.ok=true
+}
+
+func TestSlogSharesLoggerMutex(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := log.NewLoggerWithFormat(buf, log.LevelDebug, log.LevelFatal, log.SimpleLoggerFormat())
+	s := l.S(false)
+	js := l.S(true)
+
+	const n = 100
+	wg := sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			l.Info("text")
+		}()
+		go func() {
+			defer wg.Done()
+			s.Info("slog", "i", i)
+		}()
+		go func() {
+			defer wg.Done()
+			js.Info("json")
+		}()
+	}
+	wg.Wait()
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3*n)
+	for _, line := range lines {
+		ok := bytes.Equal(line, []byte("text")) ||
+			bytes.HasPrefix(line, []byte("slog i=")) ||
+			bytes.Equal(line, []byte(`{"level":"INFO","msg":"json"}`))
+		require.True(t, ok, string(line))
+	}
+}
+
+// levelRecorder records the level of each write.
+type levelRecorder struct {
+	bytes.Buffer
+	levels []log.Level
+}
+
+func (w *levelRecorder) WriteLevel(level log.Level, p []byte) (int, error) {
+	w.levels = append(w.levels, level)
+	return w.Write(p)
+}
+
+func TestSlogJSONSamplingAndLevel(t *testing.T) {
+	out := &levelRecorder{}
+	l := log.NewLoggerWithFormat(out, log.LevelDebug, log.LevelFatal, log.SimpleLoggerFormat())
+	l.SetSampling(log.SamplingOptions{Interval: time.Hour, First: 1})
+	log.SetGlobalVerbose(0)
+
+	js := l.S(true)
+	for i := 0; i < 3; i++ {
+		js.Warn("hot", "i", i)
+		js.Debug("cold", "i", i)
+	}
+
+	require.Equal(t, `{"level":"WARN","msg":"hot","i":0}
+{"level":"DEBUG","msg":"cold","i":0}
+`, out.String())
+	require.Equal(t, []log.Level{log.LevelWarn, log.LevelDebug}, out.levels)
+}
+
+func ExampleTeeLogger_S() {
+	l1buf := bytes.NewBuffer(nil)
+	l2buf := bytes.NewBuffer(nil)
+	l1 := log.NewLoggerWithFormat(l1buf, log.LevelDebug, log.LevelInfo, log.TestLoggerFormat())
+	l2 := log.NewLoggerWithFormat(l2buf, log.LevelWarn, log.LevelFatal, log.SimpleLoggerFormat())
+	log.SetGlobalLogger(log.NewTeeLogger(l1, l2))
+	log.SetGlobalVerbose(0)
+
+	s := log.S(false)
+	s.Info("Info", "attr", "InfoString")
+	s.Warn("Warn", "attr", "WarnString")
+
+	fmt.Print("logger1:\n", l1buf.String())
+	fmt.Print("logger2:\n", l2buf.String())
+
+	// Output:
+	// logger1:
+	// INFO  V0 log_test.ExampleTeeLogger_S Info attr=InfoString
+	// logger2:
+	// Warn attr=WarnString
+}
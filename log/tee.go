@@ -1,6 +1,7 @@
 package log
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -12,20 +13,32 @@ import (
 
 type TeeLogger struct {
 	loggers []ILogger
+	levels  *levelRange
 }
 
 func NewTeeLogger(loggers ...ILogger) *TeeLogger {
 	tee := &TeeLogger{}
 	tee.loggers = loggers
+	tee.levels = newLevelRange(LevelNone, LevelAll)
 	return tee
 }
 
+// clone returns a tee of loggers which shares the levels with tee.
+func (tee *TeeLogger) clone(loggers []ILogger) *TeeLogger {
+	newTee := NewTeeLogger(loggers...)
+	newTee.levels = tee.levels
+	return newTee
+}
+
 func (tee *TeeLogger) GetWriter(level Level) io.Writer {
 	ws := make([]io.Writer, 0, 1)
-	for _, l := range tee.loggers {
-		w := l.GetWriter(level)
-		if w != io.Discard {
-			ws = append(ws, w)
+	if tee.inRange(level) {
+		var buf [4]int
+		for _, i := range tee.route(level, true, buf[:0]) {
+			w := tee.loggers[i].GetWriter(level)
+			if w != io.Discard {
+				ws = append(ws, w)
+			}
 		}
 	}
 
@@ -42,7 +55,7 @@ func (tee *TeeLogger) V(v int) ILogger {
 		loggers = append(loggers, l.V(v))
 	}
 
-	return NewTeeLogger(loggers...)
+	return tee.clone(loggers)
 }
 
 func (tee *TeeLogger) With(prefix string) ILogger {
@@ -51,7 +64,16 @@ func (tee *TeeLogger) With(prefix string) ILogger {
 		loggers = append(loggers, l.With(prefix))
 	}
 
-	return NewTeeLogger(loggers...)
+	return tee.clone(loggers)
+}
+
+func (tee *TeeLogger) WithFields(kv ...any) ILogger {
+	loggers := make([]ILogger, 0, len(tee.loggers))
+	for _, l := range tee.loggers {
+		loggers = append(loggers, l.WithFields(kv...))
+	}
+
+	return tee.clone(loggers)
 }
 
 func (tee *TeeLogger) S(json bool) *slog.Logger {
@@ -62,12 +84,68 @@ func (tee *TeeLogger) S(json bool) *slog.Logger {
 }
 
 func (tee *TeeLogger) enabled(level Level) bool {
+	return tee.allows(level, true)
+}
+
+func (tee *TeeLogger) allows(level Level, widen bool) bool {
+	if !tee.inRange(level) {
+		return false
+	}
+	var buf [4]int
+	return len(tee.route(level, widen, buf[:0])) > 0
+}
+
+func (tee *TeeLogger) inRange(level Level) bool {
+	if level == LevelAll {
+		return true
+	}
+	minLevel, maxLevel := tee.levels.get()
+	return level >= minLevel && level <= maxLevel
+}
+
+// lowestLevel returns the lowest min level of the loggers within the levels of tee.
+func (tee *TeeLogger) lowestLevel() Level {
+	lowest := LevelAll
+	for _, l := range tee.loggers {
+		lowest = min(lowest, l.lowestLevel())
+	}
+	minLevel, _ := tee.levels.get()
+	return max(lowest, minLevel)
+}
+
+// route appends the index of each logger the records of level are passed to.
+// A logger takes the records by its own levels, the prefix levels can only raise its min level.
+// If no logger takes them and widen is true, the prefix levels may lower the min levels,
+// and only the loggers with the lowest min level take them,
+// e.g. the debug records of a prefix go to stdout but not stderr of ConsoleLogger.
+func (tee *TeeLogger) route(level Level, widen bool, routes []int) []int {
+	n := len(routes)
+	for i, l := range tee.loggers {
+		if l.allows(level, false) {
+			routes = append(routes, i)
+		}
+	}
+	if len(routes) > n || !widen {
+		return routes
+	}
+
+	lowest, found := LevelAll, false
 	for _, l := range tee.loggers {
-		if l.enabled(level) {
-			return true
+		if l.allows(level, true) {
+			lowest = min(lowest, l.lowestLevel())
+			found = true
+		}
+	}
+	if !found {
+		return routes
+	}
+
+	for i, l := range tee.loggers {
+		if l.lowestLevel() == lowest && l.allows(level, true) {
+			routes = append(routes, i)
 		}
 	}
-	return false
+	return routes
 }
 
 func (tee *TeeLogger) Print(a ...any) {
@@ -100,13 +178,11 @@ func (tee *TeeLogger) Printlnf(format string, a ...any) {
 	io.WriteString(w, "\n")
 }
 
-func (tee *TeeLogger) output(calldepth int, level Level, msg string) error {
+func (tee *TeeLogger) output(calldepth int, level Level, template, msg string, attrs []slog.Attr) error {
 	var err error
-	for _, l := range tee.loggers {
-		if !l.enabled(level) {
-			continue
-		}
-		err = errors.Join(err, l.output(calldepth+1, level, msg))
+	var buf [4]int
+	for _, i := range tee.route(level, true, buf[:0]) {
+		err = errors.Join(err, tee.loggers[i].output(calldepth+1, level, template, msg, attrs))
 	}
 
 	return err
@@ -117,7 +193,7 @@ func (tee *TeeLogger) Debug(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	tee.output(3, LevelDebug, msg)
+	tee.output(3, LevelDebug, "", msg, nil)
 }
 
 func (tee *TeeLogger) Debugf(format string, a ...any) {
@@ -125,7 +201,7 @@ func (tee *TeeLogger) Debugf(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	tee.output(3, LevelDebug, msg)
+	tee.output(3, LevelDebug, format, msg, nil)
 }
 
 func (tee *TeeLogger) Info(a ...any) {
@@ -133,7 +209,7 @@ func (tee *TeeLogger) Info(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	tee.output(3, LevelInfo, msg)
+	tee.output(3, LevelInfo, "", msg, nil)
 }
 
 func (tee *TeeLogger) Infof(format string, a ...any) {
@@ -141,7 +217,7 @@ func (tee *TeeLogger) Infof(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	tee.output(3, LevelInfo, msg)
+	tee.output(3, LevelInfo, format, msg, nil)
 }
 
 func (tee *TeeLogger) Warn(a ...any) {
@@ -149,7 +225,7 @@ func (tee *TeeLogger) Warn(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	tee.output(3, LevelWarn, msg)
+	tee.output(3, LevelWarn, "", msg, nil)
 }
 
 func (tee *TeeLogger) Warnf(format string, a ...any) {
@@ -157,7 +233,7 @@ func (tee *TeeLogger) Warnf(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	tee.output(3, LevelWarn, msg)
+	tee.output(3, LevelWarn, format, msg, nil)
 }
 
 func (tee *TeeLogger) Error(a ...any) {
@@ -165,7 +241,7 @@ func (tee *TeeLogger) Error(a ...any) {
 		return
 	}
 	msg := fmt.Sprint(a...)
-	tee.output(3, LevelError, msg)
+	tee.output(3, LevelError, "", msg, nil)
 }
 
 func (tee *TeeLogger) Errorf(format string, a ...any) {
@@ -173,7 +249,63 @@ func (tee *TeeLogger) Errorf(format string, a ...any) {
 		return
 	}
 	msg := fmt.Sprintf(format, a...)
-	tee.output(3, LevelError, msg)
+	tee.output(3, LevelError, format, msg, nil)
+}
+
+func (tee *TeeLogger) Debugw(msg string, kv ...any) {
+	if !tee.enabled(LevelDebug) {
+		return
+	}
+	tee.output(3, LevelDebug, msg, msg, argsToAttrs(kv))
+}
+
+func (tee *TeeLogger) Infow(msg string, kv ...any) {
+	if !tee.enabled(LevelInfo) {
+		return
+	}
+	tee.output(3, LevelInfo, msg, msg, argsToAttrs(kv))
+}
+
+func (tee *TeeLogger) Warnw(msg string, kv ...any) {
+	if !tee.enabled(LevelWarn) {
+		return
+	}
+	tee.output(3, LevelWarn, msg, msg, argsToAttrs(kv))
+}
+
+func (tee *TeeLogger) Errorw(msg string, kv ...any) {
+	if !tee.enabled(LevelError) {
+		return
+	}
+	tee.output(3, LevelError, msg, msg, argsToAttrs(kv))
+}
+
+func (tee *TeeLogger) DebugContext(ctx context.Context, msg string, kv ...any) {
+	if !tee.enabled(LevelDebug) {
+		return
+	}
+	tee.output(3, LevelDebug, msg, msg, contextArgsToAttrs(ctx, kv))
+}
+
+func (tee *TeeLogger) InfoContext(ctx context.Context, msg string, kv ...any) {
+	if !tee.enabled(LevelInfo) {
+		return
+	}
+	tee.output(3, LevelInfo, msg, msg, contextArgsToAttrs(ctx, kv))
+}
+
+func (tee *TeeLogger) WarnContext(ctx context.Context, msg string, kv ...any) {
+	if !tee.enabled(LevelWarn) {
+		return
+	}
+	tee.output(3, LevelWarn, msg, msg, contextArgsToAttrs(ctx, kv))
+}
+
+func (tee *TeeLogger) ErrorContext(ctx context.Context, msg string, kv ...any) {
+	if !tee.enabled(LevelError) {
+		return
+	}
+	tee.output(3, LevelError, msg, msg, contextArgsToAttrs(ctx, kv))
 }
 
 func (tee *TeeLogger) ErrorAt(err error, a ...any) error {
@@ -185,7 +317,7 @@ func (tee *TeeLogger) ErrorAt(err error, a ...any) error {
 	if !tee.enabled(LevelError) {
 		return err
 	}
-	tee.output(3, LevelError, errors.GetErrorDetails(err))
+	tee.output(3, LevelError, "", errors.GetErrorDetails(err), nil)
 	return err
 }
 
@@ -198,14 +330,14 @@ func (tee *TeeLogger) ErrorAtf(err error, format string, a ...any) error {
 	if !tee.enabled(LevelError) {
 		return err
 	}
-	tee.output(3, LevelError, errors.GetErrorDetails(err))
+	tee.output(3, LevelError, format, errors.GetErrorDetails(err), nil)
 	return err
 }
 
 func (tee *TeeLogger) Fatal(a ...any) {
 	if tee.enabled(LevelFatal) {
 		msg := fmt.Sprint(a...)
-		tee.output(3, LevelFatal, msg)
+		tee.output(3, LevelFatal, "", msg, nil)
 	}
 	os.Exit(1)
 }
@@ -213,7 +345,7 @@ func (tee *TeeLogger) Fatal(a ...any) {
 func (tee *TeeLogger) Fatalf(format string, a ...any) {
 	if tee.enabled(LevelFatal) {
 		msg := fmt.Sprintf(format, a...)
-		tee.output(3, LevelFatal, msg)
+		tee.output(3, LevelFatal, format, msg, nil)
 	}
 	os.Exit(1)
 }
@@ -221,7 +353,7 @@ func (tee *TeeLogger) Fatalf(format string, a ...any) {
 func (tee *TeeLogger) Panic(a ...any) {
 	msg := fmt.Sprint(a...)
 	if tee.enabled(LevelPanic) {
-		tee.output(3, LevelPanic, msg)
+		tee.output(3, LevelPanic, "", msg, nil)
 	}
 	panic(msg)
 }
@@ -229,7 +361,7 @@ func (tee *TeeLogger) Panic(a ...any) {
 func (tee *TeeLogger) Panicf(format string, a ...any) {
 	msg := fmt.Sprintf(format, a...)
 	if tee.enabled(LevelPanic) {
-		tee.output(3, LevelPanic, msg)
+		tee.output(3, LevelPanic, format, msg, nil)
 	}
 	panic(msg)
 }
@@ -0,0 +1,218 @@
+package log
+
+import (
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jopbrown/gobase/errors"
+	"github.com/jopbrown/gobase/strutil"
+)
+
+type vmoduleRule struct {
+	pattern string
+	re      *regexp.Regexp
+	file    bool
+	// segments is the number of the trailing path elements of the source file matched by a file rule.
+	segments int
+	verbose  int
+}
+
+// vmodule is the parsed rules, a new one is installed on every change,
+// so its address is the key of the caches.
+type vmodule struct {
+	spec  string
+	rules []vmoduleRule
+	// firstFileRule is the index of the first file rule, or len(rules) if none.
+	firstFileRule int
+}
+
+var (
+	globalVModule atomic.Pointer[vmodule]
+	logPkgPrefix  = funcPkgPrefix(reflect.ValueOf(getCallerPC).Pointer())
+)
+
+var vmodulePCs = struct {
+	mu      sync.RWMutex
+	matches map[uintptr]*vmodulePC
+}{matches: make(map[uintptr]*vmodulePC)}
+
+const slogPkgPrefix = "log/slog."
+
+func funcPkgPrefix(pc uintptr) string {
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndexByte(name, '/')
+	dot := strings.IndexByte(name[slash+1:], '.')
+	return name[:slash+1+dot+1]
+}
+
+func parseVModule(spec string) (*vmodule, error) {
+	vm := &vmodule{spec: spec, firstFileRule: -1}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		pattern, v, ok := strings.Cut(item, "=")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || pattern == "" {
+			return nil, errors.Errorf("invalid vmodule rule: %q", item)
+		}
+		verbose, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || verbose < 0 {
+			return nil, errors.Errorf("invalid verbose of vmodule rule: %q", item)
+		}
+		re, err := strutil.ComplieGlob(pattern)
+		if err != nil {
+			return nil, errors.ErrorAtf(err, "invalid pattern of vmodule rule: %q", item)
+		}
+
+		rule := vmoduleRule{pattern: pattern, re: re, verbose: verbose}
+		if strings.HasSuffix(pattern, ".go") {
+			rule.file = true
+			rule.segments = strings.Count(pattern, "/") + 1
+			if vm.firstFileRule < 0 {
+				vm.firstFileRule = len(vm.rules)
+			}
+		}
+		vm.rules = append(vm.rules, rule)
+	}
+	if vm.firstFileRule < 0 {
+		vm.firstFileRule = len(vm.rules)
+	}
+	return vm, nil
+}
+
+// SetVModule sets the verbose thresholds which replace the global verbose for the matched loggers,
+// spec is the comma-separated rules like "db/*=3,http=1,*.go=0".
+// The pattern of a rule is a glob matched against the prefix built by With,
+// or against the source file of the call site if it ends with ".go",
+// the trailing path elements of the file are matched if the pattern has '/'.
+// The first matched rule wins, an empty spec removes all rules.
+func SetVModule(spec string) error {
+	vm, err := parseVModule(spec)
+	if err != nil {
+		return err
+	}
+	if len(vm.rules) == 0 {
+		globalVModule.Store(nil)
+		return nil
+	}
+	globalVModule.Store(vm)
+	return nil
+}
+
+// VModule returns the spec set by SetVModule.
+func VModule() string {
+	vm := globalVModule.Load()
+	if vm == nil {
+		return ""
+	}
+	return vm.spec
+}
+
+type vmoduleMatch struct {
+	vm      *vmodule
+	index   int
+	verbose int
+}
+
+// matchPrefix returns the first prefix rule matched by prefix, index is len(vm.rules) if none.
+func (vm *vmodule) matchPrefix(prefix string) vmoduleMatch {
+	for i, rule := range vm.rules {
+		if !rule.file && rule.re.MatchString(prefix) {
+			return vmoduleMatch{vm: vm, index: i, verbose: rule.verbose}
+		}
+	}
+	return vmoduleMatch{vm: vm, index: len(vm.rules)}
+}
+
+// matchFile returns the first file rule matched by file, index is len(vm.rules) if none.
+func (vm *vmodule) matchFile(file string) vmoduleMatch {
+	for i, rule := range vm.rules {
+		if rule.file && rule.re.MatchString(trimSourceFile(file, rule.segments)) {
+			return vmoduleMatch{vm: vm, index: i, verbose: rule.verbose}
+		}
+	}
+	return vmoduleMatch{vm: vm, index: len(vm.rules)}
+}
+
+type vmodulePC struct {
+	vmoduleMatch
+	internal bool
+}
+
+// matchCaller returns the first file rule matched by the first call site outside of this package and log/slog,
+// the result of each pc is cached until the rules are changed.
+func (vm *vmodule) matchCaller() vmoduleMatch {
+	// the call site is usually within a few frames, the deeper ones are walked only if needed
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:4])
+	if m, ok := vm.matchPCs(pcs[:n]); ok || n < 4 {
+		return m
+	}
+	n = runtime.Callers(3+4, pcs[4:])
+	m, _ := vm.matchPCs(pcs[4 : 4+n])
+	return m
+}
+
+func (vm *vmodule) matchPCs(pcs []uintptr) (vmoduleMatch, bool) {
+	for _, pc := range pcs {
+		vmodulePCs.mu.RLock()
+		m := vmodulePCs.matches[pc]
+		vmodulePCs.mu.RUnlock()
+
+		if m == nil || m.vm != vm {
+			m = &vmodulePC{vmoduleMatch: vmoduleMatch{vm: vm, index: len(vm.rules)}}
+			if frame := getFrame(pc); frame != nil {
+				m.internal = strings.HasPrefix(frame.Function, logPkgPrefix) || strings.HasPrefix(frame.Function, slogPkgPrefix)
+				if !m.internal {
+					m.vmoduleMatch = vm.matchFile(frame.File)
+				}
+			}
+
+			vmodulePCs.mu.Lock()
+			vmodulePCs.matches[pc] = m
+			vmodulePCs.mu.Unlock()
+		}
+
+		if !m.internal {
+			return m.vmoduleMatch, true
+		}
+	}
+	return vmoduleMatch{vm: vm, index: len(vm.rules)}, false
+}
+
+// verboseThreshold returns the max verbose enabled for l,
+// it is the global verbose unless a rule of vmodule is matched.
+func (l *Logger) verboseThreshold() int {
+	vm := globalVModule.Load()
+	if vm == nil {
+		return int(globalVerbose.Load())
+	}
+
+	m := l.vmoduleCache.Load()
+	if m == nil || m.vm != vm {
+		pm := vm.matchPrefix(l.prefix)
+		m = &pm
+		l.vmoduleCache.Store(m)
+	}
+	match := *m
+
+	// the call site is looked up only if a file rule may win
+	if vm.firstFileRule < match.index {
+		if fm := vm.matchCaller(); fm.index < match.index {
+			match = fm
+		}
+	}
+
+	if match.index >= len(vm.rules) {
+		return int(globalVerbose.Load())
+	}
+	return match.verbose
+}
@@ -0,0 +1,93 @@
+package log_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/jopbrown/gobase/log"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSetVModule() {
+	log.SetGlobalLogger(log.NewLoggerWithFormat(os.Stdout, log.LevelInfo, log.LevelFatal, log.TestLoggerFormat()))
+	log.SetGlobalVerbose(0)
+	defer log.SetVModule("")
+
+	log.SetVModule("db/*=3,http=1")
+	log.With("db").V(1).Info("hidden")
+	log.With("db/sql").V(3).Info("db/sql V3")
+	log.With("http").V(1).Info("http V1")
+	log.With("http").V(2).Info("hidden")
+	log.V(1).Info("hidden")
+
+	// Output:
+	// INFO  V3 log_test.ExampleSetVModule db/sql db/sql V3
+	// INFO  V1 log_test.ExampleSetVModule http http V1
+}
+
+func TestNegativeGlobalVerbose(t *testing.T) {
+	defer log.SetVModule("")
+	buf := bytes.NewBuffer(nil)
+	l := log.NewLoggerWithFormat(buf, log.LevelInfo, log.LevelFatal, log.SimpleLoggerFormat())
+	log.SetGlobalVerbose(-1)
+	defer log.SetGlobalVerbose(0)
+
+	l.Info("hidden")
+	l.V(1).Info("hidden")
+	l.V(-1).Info("v-1")
+
+	require.NoError(t, log.SetVModule("http=0"))
+	l.Info("hidden")
+	l.With("http").Info("http v0")
+
+	require.Equal(t, "v-1\nhttp http v0\n", buf.String())
+}
+
+func TestSetVModuleFile(t *testing.T) {
+	defer log.SetVModule("")
+	buf := bytes.NewBuffer(nil)
+	l := log.NewLoggerWithFormat(buf, log.LevelInfo, log.LevelFatal, log.SimpleLoggerFormat())
+	log.SetGlobalVerbose(3)
+	defer log.SetGlobalVerbose(0)
+
+	require.NoError(t, log.SetVModule("log/vmodule_test.go=1"))
+	l.V(1).Info("v1")
+	l.V(2).Info("hidden")
+	l.V(2).S(false).Info("hidden")
+	log.NewTeeLogger(l).V(1).S(false).Info("tee v1")
+
+	require.NoError(t, log.SetVModule("http=2, *_test.go=0"))
+	l.V(1).Info("hidden")
+	l.With("http").V(2).Info("v2")
+
+	require.NoError(t, log.SetVModule("*.go=0, http=2"))
+	l.With("http").V(2).Info("hidden")
+	l.Info("v0")
+
+	require.NoError(t, log.SetVModule(""))
+	l.V(3).Info("v3")
+
+	require.Equal(t, "v1\ntee v1\nhttp v2\nv0\nv3\n", buf.String())
+}
+
+func TestSetVModuleInvalid(t *testing.T) {
+	defer log.SetVModule("")
+	require.NoError(t, log.SetVModule("db=1"))
+
+	require.Error(t, log.SetVModule("db"))
+	require.Error(t, log.SetVModule("=1"))
+	require.Error(t, log.SetVModule("db=-1"))
+	require.Error(t, log.SetVModule("db=x"))
+	require.Equal(t, "db=1", log.VModule())
+}
+
+func BenchmarkLoggerEnabledVModule(b *testing.B) {
+	defer log.SetVModule("")
+	l := log.NewLogger(bytes.NewBuffer(nil), log.LevelInfo, log.LevelFatal).With("db").V(3)
+	log.SetVModule("db/*=3,http=1,*_test.go=2")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("hidden")
+	}
+}